@@ -124,7 +124,7 @@ func (c *Command) run(ctx context.Context, name string, args []string, parentDef
 		subNames = append(subNames, name)
 	}
 
-	var allDefs harg.Definitions
+	allDefs := make(harg.Definitions)
 	for _, flag := range c.Flags {
 		flag := flag.flag()
 		def := flag.def()
@@ -134,7 +134,19 @@ func (c *Command) run(ctx context.Context, name string, args []string, parentDef
 		}
 	}
 
-	cleanArgs, choke, err := allDefs.Parse(args, subNames)
+	log := c.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	p := harg.Parser{
+		Definitions: allDefs, Chokes: subNames,
+		Warn: func(deprecated string, deprecation harg.Deprecation) {
+			log.Warn("deprecated", slog.String("name", deprecated), slog.String("replacement", deprecation.Replacement),
+				slog.String("message", deprecation.Message), slog.String("command_name", name))
+		},
+	}
+	cleanArgs, choke, err := p.Parse(args)
 	if err != nil {
 		c.Logger.Error("parsing arguments", err, slog.String("parse_type", "initial"), slog.String("command_name", name))
 		return ExitUsage
@@ -162,13 +174,13 @@ func (c *Command) run(ctx context.Context, name string, args []string, parentDef
 	return c.Action(ctx, cleanArgs, defs, c.Logger)
 }
 
-// TODO:
+// Definitions of current level shadow previous ones.
 func mergeDefs(previousLevel, currentLevel harg.Definitions) harg.Definitions {
-	if len(previousLevel) == 0 {
-		return currentLevel
-	}
+	defs := make(harg.Definitions, len(previousLevel)+len(currentLevel))
+	_ = defs.Merge(previousLevel, harg.MergeOverride) // no error with MergeOverride
+	_ = defs.Merge(currentLevel, harg.MergeOverride)
 
-	panic("merge not implemented") // TODO:
+	return defs
 }
 
 // func (c *Command) normalize() error {
@@ -20,10 +20,13 @@ type (
 
 		Env    string
 		EnvCSV bool
+		File   bool // see harg.Definition.File
 
-		Default any // value to set when nothing is set
+		Default   any  // value to set when nothing is set
+		Sensitive bool // value is hidden in logs and errors, see harg.Definition.Sensitive
 
-		Usage string
+		Usage      string
+		Deprecated map[string]harg.Deprecation // key: any of Options or Env, see harg.Definition.Deprecated
 
 		child *ChildFlag
 	}
@@ -39,8 +42,13 @@ var flagLevelMax = Parent
 
 func (f *flag) def() harg.Definition {
 	return harg.Definition{
-		Type:     f.Type,
-		AlsoBool: f.AlsoBool,
-		EnvCSV:   f.EnvCSV,
+		Type:         f.Type,
+		AlsoBool:     f.AlsoBool,
+		EnvCSV:       f.EnvCSV,
+		DefaultValue: f.Default,
+		Deprecated:   f.Deprecated,
+		Sensitive:    f.Sensitive,
+		File:         f.File,
+		Usage:        f.Usage,
 	}
 }
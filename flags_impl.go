@@ -79,9 +79,11 @@ type (
 		EnvCSV bool
 
 		Default   []bool // value to set when nothing is set
+		Sensitive bool   // value is hidden in logs and errors, see harg.Definition.Sensitive
 		Condition BoolCondition
 
-		Usage string
+		Usage      string
+		Deprecated map[string]harg.Deprecation // key: any of Options or Env, see harg.Definition.Deprecated
 	}
 	BoolCondition func(flagDefault []bool, def *harg.Definition) error
 )
@@ -98,6 +100,9 @@ func (f *BoolFlag) flag() flag {
 		Env:     f.Env,
 		EnvCSV:  f.EnvCSV,
 		Usage:   f.Usage,
+
+		Deprecated: f.Deprecated,
+		Sensitive:  f.Sensitive,
 	}
 }
 
@@ -117,11 +122,14 @@ type (
 
 		Env    string
 		EnvCSV bool
+		File   bool // see harg.Definition.File
 
 		Default   []string // value to set when nothing is set
+		Sensitive bool     // value is hidden in logs and errors, see harg.Definition.Sensitive
 		Condition StringCondition
 
-		Usage string
+		Usage      string
+		Deprecated map[string]harg.Deprecation // key: any of Options or Env, see harg.Definition.Deprecated
 	}
 	StringCondition func(flagDefault []string, def *harg.Definition) error
 )
@@ -136,7 +144,11 @@ func (f *StringFlag) flag() flag {
 		Env:      f.Env,
 		EnvCSV:   f.EnvCSV,
 		AlsoBool: f.AlsoBool,
+		File:     f.File,
 		Usage:    f.Usage,
+
+		Deprecated: f.Deprecated,
+		Sensitive:  f.Sensitive,
 	}
 }
 
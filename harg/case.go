@@ -0,0 +1,80 @@
+package harg
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type CaseMode uint8 // enum:
+const (
+	CaseInsensitive CaseMode = iota // simple Unicode case folding (as strings.EqualFold)
+	CaseSensitive
+)
+
+// Key used for matching.
+func (m CaseMode) normalize(s string) string {
+	if m == CaseSensitive {
+		return s
+	}
+
+	return foldCase(s)
+}
+
+// Canonical case folded form of s, strings.EqualFold(a, b) == (foldCase(a) == foldCase(b)).
+func foldCase(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= utf8.RuneSelf || 'A' <= c && c <= 'Z' {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s // common case, no allocation
+	}
+
+	return strings.Map(foldRune, s)
+}
+
+// foldCase(s) appended to dst.
+func appendFoldCase(dst []byte, s string) []byte {
+	for i, r := range s {
+		if r < utf8.RuneSelf {
+			c := s[i]
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+
+			dst = append(dst, c)
+			continue
+		}
+
+		dst = utf8.AppendRune(dst, foldRune(r))
+	}
+
+	return dst
+}
+
+// Canonical rune of r's case folding orbit (K, k, K (Kelvin)), preferring lowercase.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		return unicode.ToLower(r)
+	}
+
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+
+	lower := unicode.ToLower(min)
+	for f := unicode.SimpleFold(min); lower != min && f != min; f = unicode.SimpleFold(f) {
+		if f == lower {
+			return lower
+		}
+	}
+
+	return min
+}
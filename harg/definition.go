@@ -6,6 +6,9 @@ import (
 	"strings"
 	"unicode"
 	"unicode/utf8"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type (
@@ -24,11 +27,61 @@ type (
 		// defs.ParseEnv(): If enabled, environment value will be split by commas (to slice).
 		EnvCSV bool
 
-		originalType Type // used in parsing AlsoBool
-		parsed       option
+		// How values of repeated options (and lists) are stored, default: SliceAppend.
+		Slice SliceMode
+
+		// Deprecated names (options or environment) of the Definition, key: name as in Definitions.
+		// Deprecated names keep working, but parsing warns (see Parser.Warn). See Definitions.Deprecate().
+		Deprecated map[string]Deprecation
+
+		// Called with each parsed value (of Type's Go type, bool for AlsoBool bools), from any Source but DefaultValue.
+		// Errors are wrapped as ErrIncompatibleValue.
+		Validate func(v any) error
+
+		// Value is hidden ("REDACTED") in fmt, slog, parsing errors and Export(). Getters and Render() are not affected.
+		Sensitive bool
+
+		// Values may be read from files (trimmed of surrounding whitespace):
+		//   options: `--key=@path`, `-k @path`; "@-" reads stdin (Parser.Stdin), "@@" escapes a literal "@".
+		//   environment: KEY_FILE=path, for environment KEY.
+		File bool
+
+		// If set, values are expanded before parsing, looking variables up with Expand (eg os.LookupEnv):
+		// `$VAR`, `${VAR}`, `${VAR:-default}` (default if unset or empty, expanded), `$$` (literal "$"), and a leading `~` (HOME, "~~" escapes "~").
+		// Unset variables are empty. Expanded after Slice splitting. For File, the path is expanded, not the contents.
+		Expand func(key string) (value string, ok bool)
+
+		// Description, see Schema().
+		Usage string
+
+		// Allowed values (of Type's Go type), checked before Validate. Bools of AlsoBool are not checked.
+		Enum []any
+
+		// Must have a value (from any Source, including DefaultValue), see Definitions.CheckRequired().
+		Required bool
+
+		// Value used when not set by Parse(), ParseEnv() or ParseConfig().
+		// Must be of Type's Go type (T) or a slice of it ([]T), eg "foo" or []string{"foo"} for String.
+		// AlsoBool may also have a Bool default. Stored on normalization (eg Parse()), changes after are not seen.
+		DefaultValue any
+
+		// Values from each Source are kept separately, the highest Source replaces lower ones.
+		// If enabled, values from all Sources are joined instead (lowest first).
+		MergeSources bool
+
+		layers   [SourceMax + 1]option // index: Source
+		parsed   option                // resolved from layers
+		longCase CaseMode              // of normalization (Parser.LongCase), for AliasGroups()
 	}
 )
 
+type SliceMode uint8 // enum:
+const (
+	SliceAppend  SliceMode = iota // --tag a --tag b,c: [a b,c]
+	SliceReplace                  // --tag a --tag b,c: [b,c] (last wins, within a Source)
+	SliceSplit                    // --tag a --tag b,c: [a b c] (comma-separated, as EnvCSV)
+)
+
 func (defs Definitions) Alias(name string, target string) error {
 	defP, ok := defs[target]
 	if !ok {
@@ -39,6 +92,51 @@ func (defs Definitions) Alias(name string, target string) error {
 	return nil
 }
 
+// See Definition.Deprecated.
+type Deprecation struct {
+	Replacement string // name to use instead, optional
+	Message     string // optional
+}
+
+// Marks name (of an existing Definition) as deprecated. Typically the old name is an Alias() of the new.
+func (defs Definitions) Deprecate(name string, deprecation Deprecation) error {
+	def, ok := defs[name]
+	if !ok {
+		return fmt.Errorf("definition name %s: %w", name, ErrOptionHasNoDefinition)
+	}
+
+	if def.Deprecated == nil {
+		def.Deprecated = make(map[string]Deprecation)
+	}
+
+	def.Deprecated[name] = deprecation
+	return nil
+}
+
+// Errors with ErrMissingArgument on the first (by canonical name, see AliasGroups()) Required Definition without a value.
+func (defs Definitions) CheckRequired() error {
+	for _, g := range defs.AliasGroups() {
+		if g.Definition.Required && g.Definition.value() == nil {
+			return fmt.Errorf("%w %s", ErrMissingArgument, g.displayName())
+		}
+	}
+
+	return nil
+}
+
+// Canonical name as used: "--long", "-s", "ENV".
+func (g AliasGroup) displayName() string {
+	name := g.Canonical()
+	switch {
+	case len(g.Long) != 0:
+		return "--" + name
+	case len(g.Short) != 0:
+		return "-" + name
+	default:
+		return name
+	}
+}
+
 // TODO: hcli
 // Does not overwrite existing (case-sensitive) definition names.
 func (defs Definitions) SetUnique(name string, def *Definition) (ok bool) {
@@ -50,17 +148,6 @@ func (defs Definitions) SetUnique(name string, def *Definition) (ok bool) {
 	return true
 }
 
-func (defs Definitions) get(key string) (*Definition, error) {
-	key = strings.ToLower(key)
-
-	def, ok := defs[key]
-	if ok {
-		return def, nil
-	}
-
-	return nil, fmt.Errorf("%s: %w", optErrorName(key), ErrOptionHasNoDefinition)
-}
-
 func (defs Definitions) genericNormalize(transform func(key string, def *Definition) (newKey string, _ error)) error {
 	for key, def := range defs {
 		if def == nil || key == "" {
@@ -85,6 +172,20 @@ func (defs Definitions) genericNormalize(transform func(key string, def *Definit
 			def.AlsoBool = false // for parseOptionContent()
 		}
 
+		for _, v := range def.Enum {
+			if !def.isValueType(v) {
+				return fmt.Errorf("%s: %w", optErrorName(key), genericErr{
+					Err: ErrInvalidDefinition, Wrapped: fmt.Errorf("Enum value of type %T does not match Type %s", v, def.Type),
+				})
+			}
+		}
+
+		if err := def.initDefault(); err != nil {
+			return fmt.Errorf("%s: %w", optErrorName(key), genericErr{
+				Err: ErrInvalidDefinition, Wrapped: err,
+			})
+		}
+
 		new, err := transform(key, def)
 		if err != nil {
 			return fmt.Errorf("%s: %w", optErrorName(key), genericErr{
@@ -102,6 +203,10 @@ func (defs Definitions) genericNormalize(transform func(key string, def *Definit
 }
 
 func (defs Definitions) normalizeOpts() error {
+	return defs.normalizeOptsCase(CaseInsensitive)
+}
+
+func (defs Definitions) normalizeOptsCase(longCase CaseMode) error {
 	return defs.genericNormalize(func(key string, def *Definition) (string, error) {
 		// short args are case sensitive, skip
 		if utf8.RuneCountInString(key) == 1 {
@@ -110,11 +215,26 @@ func (defs Definitions) normalizeOpts() error {
 		}
 
 		// case insensitivize long args
-		return strings.ToLower(key), nil
+		def.longCase = longCase
+		if longCase == CaseSensitive {
+			return key, nil
+		}
+
+		var buf [64]byte
+		folded := appendFoldCase(buf[:0], key)
+		if defs[string(folded)] == def {
+			return key, nil // already aliased (on an earlier Parse()), without allocating
+		}
+
+		return string(folded), nil
 	})
 }
 
 func (defs Definitions) normalizeEnv() error {
+	return defs.normalizeEnvCase(CaseInsensitive)
+}
+
+func (defs Definitions) normalizeEnvCase(envCase CaseMode) error {
 	return defs.genericNormalize(func(key string, def *Definition) (string, error) {
 		for _, r := range key {
 			if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
@@ -124,11 +244,109 @@ func (defs Definitions) normalizeEnv() error {
 			return key, fmt.Errorf("must contain only underscores, letters and/or digits")
 		}
 
+		if envCase == CaseSensitive {
+			return key, nil
+		}
+
 		// capitalize all keys
 		return strings.ToUpper(key), nil
 	})
 }
 
+// Names (keys) of a Definition, see Definitions.AliasGroups().
+type AliasGroup struct {
+	Definition       *Definition
+	Short, Long, Env []string // sorted, Long case folded (deduplicated) unless normalized with Parser.LongCase CaseSensitive
+}
+
+// Groups keys by their Definition (aliases), as options (see Definitions.Parse()), sorted by canonical name (see AliasGroup.Canonical()).
+// For environment names, see Parser.AliasGroups().
+func (defs Definitions) AliasGroups() []AliasGroup {
+	return aliasGroups(defs, nil)
+}
+
+// Definitions.AliasGroups(), with keys of Parser.Env as environment names of the same Definitions.
+func (p *Parser) AliasGroups() []AliasGroup {
+	return aliasGroups(p.Definitions, p.Env)
+}
+
+func aliasGroups(opts, env Definitions) []AliasGroup {
+	index := make(map[*Definition]*AliasGroup)
+	var order []*Definition
+
+	group := func(def *Definition) *AliasGroup {
+		g, ok := index[def]
+		if !ok {
+			g = &AliasGroup{Definition: def}
+			index[def] = g
+			order = append(order, def)
+		}
+		return g
+	}
+
+	for _, key := range sortedKeys(opts) { // deterministic order of groups with the same canonical name
+		def := opts[key]
+		if def == nil || key == "" {
+			continue
+		}
+		g := group(def)
+
+		if isShortKey(key) {
+			g.Short = append(g.Short, key)
+		} else if key = def.longCase.normalize(key); !slices.Contains(g.Long, key) {
+			g.Long = append(g.Long, key)
+		}
+	}
+
+	for _, key := range sortedKeys(env) {
+		if def := env[key]; def != nil && key != "" {
+			g := group(def)
+			g.Env = append(g.Env, key)
+		}
+	}
+
+	groups := make([]AliasGroup, 0, len(order))
+	for _, def := range order {
+		g := index[def]
+		slices.Sort(g.Long) // folded
+
+		groups = append(groups, *g)
+	}
+
+	slices.SortStableFunc(groups, func(a, b AliasGroup) bool {
+		return a.Canonical() < b.Canonical()
+	})
+	return groups
+}
+
+func sortedKeys(defs Definitions) []string {
+	keys := maps.Keys(defs)
+	slices.Sort(keys)
+	return keys
+}
+
+// Longest long option, or first short option, or first environment name.
+func (g AliasGroup) Canonical() string {
+	var name string
+	for _, long := range g.Long {
+		if utf8.RuneCountInString(long) > utf8.RuneCountInString(name) {
+			name = long
+		}
+	}
+
+	for _, names := range [][]string{{name}, g.Short, g.Env} {
+		if len(names) != 0 && names[0] != "" {
+			return names[0]
+		}
+	}
+
+	return ""
+}
+
+func isShortKey(key string) bool {
+	return utf8.RuneCountInString(key) == 1
+}
+
 func optErrorName(key string) string {
 	var keyType string
 	if utf8.RuneCountInString(key) > 1 {
@@ -138,3 +138,13 @@ func TestDefinitionNormalizeBadEnv(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendFoldCase(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "lower", "Option-1", "KELVIN-\u212a", "ſtring", "ΣΟΦΟΣ", "UPPERかSÉ"} {
+		if got := string(appendFoldCase(nil, s)); got != foldCase(s) {
+			t.Errorf("appendFoldCase(%q) = %q, foldCase() = %q", s, got, foldCase(s))
+		}
+	}
+}
@@ -30,6 +30,57 @@ func TestSetAlias(t *testing.T) {
 	require.Equal(t, harg.String, defs["alias"].Type)
 }
 
+func TestAliasGroups(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"verbose": {},
+		"port":    {Type: harg.Int},
+		"e":       {},
+		"DEBUG":   {}, // uppercase long option
+	}
+	require.Nil(t, defs.Alias("v", "verbose"))
+	require.Nil(t, defs.Alias("Loud", "verbose"))
+	require.Nil(t, defs.Alias("loud", "verbose"))
+
+	p := harg.Parser{Definitions: defs, Env: harg.Definitions{
+		"VERBOSE": defs["verbose"],
+		"PORT":    defs["port"],
+		"ONLY":    {},
+	}}
+
+	var got []harg.AliasGroup
+	for i := 0; i < 3; i++ { // stable
+		got = p.AliasGroups()
+		require.Equal(t, []harg.AliasGroup{
+			{Definition: p.Env["ONLY"], Env: []string{"ONLY"}},
+			{Definition: defs["DEBUG"], Long: []string{"debug"}},
+			{Definition: defs["e"], Short: []string{"e"}},
+			{Definition: defs["port"], Long: []string{"port"}, Env: []string{"PORT"}},
+			{Definition: defs["v"], Short: []string{"v"}, Long: []string{"loud", "verbose"}, Env: []string{"VERBOSE"}},
+		}, got)
+	}
+
+	var canonical []string
+	for _, g := range got {
+		canonical = append(canonical, g.Canonical())
+	}
+	require.Equal(t, []string{"ONLY", "debug", "e", "port", "verbose"}, canonical)
+
+	// options only
+	require.Len(t, defs.AliasGroups(), 4)
+	require.Nil(t, defs.AliasGroups()[3].Env)
+
+	// same canonical name: in order of keys
+	one, two := &harg.Definition{}, &harg.Definition{}
+	p = harg.Parser{Definitions: harg.Definitions{"x": two}, Env: harg.Definitions{"x": one}}
+	for i := 0; i < 3; i++ {
+		got = p.AliasGroups()
+		require.Equal(t, two, got[0].Definition)
+		require.Equal(t, one, got[1].Definition)
+	}
+}
+
 func TestOptionGetAny(t *testing.T) {
 	for t := harg.Type(0); t <= harg.TypeMax; t++ {
 		def := harg.Definition{Type: t}
@@ -0,0 +1,36 @@
+package harg
+
+import (
+	"os"
+	"strings"
+)
+
+// See Definition.Expand.
+func expand(s string, lookup func(key string) (string, bool)) string {
+	var home string
+	if strings.HasPrefix(s, "~~") {
+		s = s[1:] // "~~": literal "~"
+	} else if s == "~" || strings.HasPrefix(s, "~/") {
+		if v, ok := lookup("HOME"); ok {
+			home, s = v, s[1:] // not expanded further
+		}
+	}
+
+	return home + expandVars(s, lookup)
+}
+
+// Variables of s, the fallback of `${VAR:-fallback}` is expanded as well (but can't contain "}").
+func expandVars(s string, lookup func(key string) (string, bool)) string {
+	return os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$" // "$$"
+		}
+
+		name, fallback, hasFallback := strings.Cut(name, ":-")
+		if v, ok := lookup(name); ok && (v != "" || !hasFallback) {
+			return v
+		}
+
+		return expandVars(fallback, lookup)
+	})
+}
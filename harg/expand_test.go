@@ -0,0 +1,60 @@
+package harg_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"HOME": "/home/foo", "CACHE": "cache", "EMPTY": ""}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	for value, want := range map[string]string{
+		"$HOME/$CACHE":      "/home/foo/cache",
+		"${CACHE}d":         "cached",
+		"${UNSET:-/tmp}/x":  "/tmp/x",
+		"${EMPTY:-default}": "default",
+		"${CACHE:-default}": "cache",
+		"${UNSET:-$HOME}/x": "/home/foo/x",
+		"$UNSET.":           ".",
+		"$$HOME":            "$HOME",
+		"~":                 "/home/foo",
+		"~/data":            "/home/foo/data",
+		"~foo":              "~foo",
+		"~~/data":           "~/data",
+		"~~":                "~",
+		"~~$CACHE":          "~cache",
+		"a~/b":              "a~/b",
+		"no variables":      "no variables",
+		"k=$CACHE,${CACHE}": "k=cache,cache",
+	} {
+		defs := harg.Definitions{"path": {Type: harg.String, Expand: lookup}}
+		_, _, err := defs.Parse([]string{"--path", value}, nil)
+		require.Nil(t, err)
+
+		s, _ := defs["path"].String()
+		require.Equal(t, want, s, value)
+	}
+
+	// opt-in
+	defs := harg.Definitions{"path": {Type: harg.String}}
+	_, _, err := defs.Parse([]string{"--path=~/$CACHE"}, nil)
+	require.Nil(t, err)
+	s, _ := defs["path"].String()
+	require.Equal(t, "~/$CACHE", s)
+
+	// config, after splitting
+	defs = harg.Definitions{"path": {Type: harg.String, Slice: harg.SliceSplit, Expand: lookup}}
+	env["LIST"] = "a,b"
+	require.Nil(t, defs.ParseConfig(strings.NewReader(`{"path": "$LIST,~"}`), harg.JSON))
+	sl, _ := defs["path"].SlString()
+	require.Equal(t, []string{"a,b", "/home/foo"}, sl)
+}
@@ -0,0 +1,80 @@
+package harg
+
+import (
+	"encoding/json"
+
+	"golang.org/x/exp/slices"
+)
+
+// State of a Definition, see Export().
+type Exported struct {
+	Key     string   `json:"key"` // canonical name, see Render()
+	Aliases []string `json:"aliases,omitempty"`
+	Type    string   `json:"type"`
+	Value   any      `json:"value,omitempty"` // as SlAny(), Duration as string
+	Default bool     `json:"default"`
+	Source  string   `json:"source,omitempty"`
+}
+
+// Whether to hide def's value in Export(). key: canonical name.
+type RedactFunc func(key string, def *Definition) bool
+
+// All Definitions with their resolved values, in order of canonical name.
+// Values are replaced with "REDACTED" if Sensitive, or redact (may be nil) returns true.
+func (defs Definitions) Export(redact RedactFunc) []Exported {
+	return export(defs.AliasGroups(), redact)
+}
+
+// Definitions.Export(), with environment names of Parser.Env as aliases.
+func (p *Parser) Export(redact RedactFunc) []Exported {
+	return export(p.AliasGroups(), redact)
+}
+
+func export(groups []AliasGroup, redact RedactFunc) []Exported {
+	exported := make([]Exported, 0, len(groups))
+
+	for _, g := range groups {
+		def := g.Definition
+		e := Exported{
+			Key:     g.Canonical(),
+			Default: def.Default(),
+		}
+
+		for _, names := range [][]string{g.Long, g.Short, g.Env} {
+			for _, name := range names {
+				if name != e.Key {
+					e.Aliases = append(e.Aliases, name)
+				}
+			}
+		}
+		slices.Sort(e.Aliases)
+
+		if v := def.value(); v != nil {
+			e.Type = def.optionType(v).String()
+
+			if src, ok := def.Source(); ok {
+				e.Source = src.String()
+			}
+
+			switch {
+			case def.Sensitive, redact != nil && redact(e.Key, def):
+				e.Value = redacted
+			case def.optionType(v) == Duration:
+				e.Value = v.format()
+			default:
+				e.Value = v.contents()
+			}
+		} else {
+			e.Type = def.Type.String()
+		}
+
+		exported = append(exported, e)
+	}
+
+	return exported
+}
+
+// Export() as indented JSON, eg for --print-config.
+func (defs Definitions) ExportJSON(redact RedactFunc) ([]byte, error) {
+	return json.MarshalIndent(defs.Export(redact), "", "  ")
+}
@@ -0,0 +1,47 @@
+package harg_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJSON(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"name":    {Type: harg.String},
+		"timeout": {Type: harg.Duration, DefaultValue: 5 * time.Second},
+		"token":   {Type: harg.String},
+		"count":   {Type: harg.Int},
+		"v":       {},
+	}
+	require.Nil(t, defs.Alias("n", "name"))
+
+	p := harg.Parser{Definitions: defs, Env: harg.Definitions{"NAME": defs["name"]}}
+	_, _, err := p.Parse([]string{"-n", "foo", "--token=secret", "-vv"})
+	require.Nil(t, err)
+
+	b, err := json.Marshal(p.Export(func(key string, _ *harg.Definition) bool {
+		return key == "token"
+	}))
+	require.Nil(t, err)
+	require.JSONEq(t, `[
+		{"key": "count", "type": "int", "default": true},
+		{"key": "name", "aliases": ["NAME", "n"], "type": "string", "value": ["foo"], "default": false, "source": "args"},
+		{"key": "timeout", "type": "duration", "value": ["5s"], "default": true, "source": "default"},
+		{"key": "token", "type": "string", "value": "REDACTED", "default": false, "source": "args"},
+		{"key": "v", "type": "bool", "value": [true, true], "default": false, "source": "args"}
+	]`, string(b))
+
+	// options only
+	b, err = defs.ExportJSON(nil)
+	require.Nil(t, err)
+	var exported []harg.Exported
+	require.Nil(t, json.Unmarshal(b, &exported))
+	require.Equal(t, "name", exported[1].Key)
+	require.Equal(t, []string{"n"}, exported[1].Aliases)
+}
@@ -0,0 +1,53 @@
+package harg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Suffix of an environment key naming a file to read the value from, see Definition.File.
+const envFileSuffix = "_FILE"
+
+// Value of an option with Definition.File: "@path" is read from path ("@-": stdin), "@@" escapes "@".
+// path is expanded with lookup (if not nil, see Definition.Expand), read is false for values not read from a file.
+func (p *Parser) fileValue(value string, lookup func(key string) (string, bool), errContext func() string) (_ string, read bool, _ error) {
+	path, ok := trimPrefix(value, "@")
+	if !ok || strings.HasPrefix(path, "@") {
+		return path, false, nil // "@@value": "@value"
+	}
+
+	if lookup != nil {
+		path = expand(path, lookup)
+	}
+
+	v, err := p.readFile(path, errContext)
+	return v, true, err
+}
+
+// Contents of file name ("-": stdin), trimmed of surrounding whitespace.
+func (p *Parser) readFile(name string, errContext func() string) (string, error) {
+	var b []byte
+	var err error
+
+	if name == "-" {
+		stdin := p.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+
+		b, err = io.ReadAll(stdin)
+	} else {
+		b, err = os.ReadFile(name)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("reading %s from file %q: %w", errContext(), name, genericErr{
+			Err:     ErrIncompatibleValue,
+			Wrapped: err,
+		})
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
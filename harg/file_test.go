@@ -0,0 +1,95 @@
+package harg_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "pw")
+	require.Nil(t, os.WriteFile(name, []byte("  hunter2\n"), 0o600))
+
+	p := harg.Parser{
+		Definitions: harg.Definitions{
+			"password": {Type: harg.String, File: true},
+			"p":        {Type: harg.String, File: true},
+			"literal":  {Type: harg.String},
+		},
+		Stdin: strings.NewReader("fromstdin\n"),
+	}
+	_, _, err := p.Parse([]string{"--password=@" + name, "-p", "@-", "--password", "@@escaped", "--literal=@" + name})
+	require.Nil(t, err)
+
+	sl, _ := p.Definitions["password"].SlString()
+	require.Equal(t, []string{"hunter2", "@escaped"}, sl)
+	s, _ := p.Definitions["p"].String()
+	require.Equal(t, "fromstdin", s)
+	s, _ = p.Definitions["literal"].String()
+	require.Equal(t, "@"+name, s)
+
+	defs := harg.Definitions{"password": {Type: harg.String, File: true}}
+	_, _, err = defs.Parse([]string{"--password=@" + name + ".missing"}, nil)
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.ErrorContains(t, err, "long option password (argument 0)")
+}
+
+func TestFileExpand(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "pw"), []byte("pa$$word"), 0o600))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "home"), []byte("~/$ecret"), 0o600))
+
+	lookup := func(key string) (string, bool) { return dir, key == "DIR" }
+	defs := harg.Definitions{"password": {Type: harg.String, File: true, Expand: lookup}}
+
+	// path is expanded, contents are not
+	_, _, err := defs.Parse([]string{"--password=@$DIR/pw", "--password=@${DIR}/home", "--password=@@$DIR"}, nil)
+	require.Nil(t, err)
+	sl, _ := defs["password"].SlString()
+	require.Equal(t, []string{"pa$$word", "~/$ecret", "@" + dir}, sl)
+}
+
+func TestFileEnv(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "pw")
+	require.Nil(t, os.WriteFile(name, []byte("hunter2\n"), 0o600))
+
+	require.Nil(t, os.Setenv("HARG_TEST_FILE_FILE", name))
+	require.Nil(t, os.Setenv("HARG_TEST_NOFILE_FILE", name))
+	defer os.Unsetenv("HARG_TEST_FILE_FILE")
+	defer os.Unsetenv("HARG_TEST_NOFILE_FILE")
+
+	defs := harg.Definitions{
+		"HARG_TEST_FILE":   {Type: harg.String, File: true},
+		"HARG_TEST_NOFILE": {Type: harg.String},
+	}
+	require.Nil(t, defs.ParseEnv())
+
+	s, _ := defs["HARG_TEST_FILE"].String()
+	require.Equal(t, "hunter2", s)
+	require.Equal(t, true, defs["HARG_TEST_NOFILE"].Default())
+
+	// path is expanded, contents are not
+	require.Nil(t, os.WriteFile(name, []byte("pa$$word"), 0o600))
+	require.Nil(t, os.Setenv("HARG_TEST_FILE_FILE", "$PW"))
+	lookup := func(key string) (string, bool) { return name, key == "PW" }
+	defs = harg.Definitions{"HARG_TEST_FILE": {Type: harg.String, File: true, Expand: lookup}}
+	require.Nil(t, defs.ParseEnv())
+	s, _ = defs["HARG_TEST_FILE"].String()
+	require.Equal(t, "pa$$word", s)
+
+	require.Nil(t, os.Setenv("HARG_TEST_FILE_FILE", name+".missing"))
+	defs = harg.Definitions{"HARG_TEST_FILE": {Type: harg.String, File: true}}
+	err := defs.ParseEnv()
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.ErrorContains(t, err, "environment HARG_TEST_FILE_FILE")
+}
@@ -0,0 +1,112 @@
+package harg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Repeatable records of options (`-i a.mp4 -ss 10 -i b.mp4 -ss 20`), see Parser.Group.
+//
+// A record starts at Head, following options of Definitions apply to it (until the next Head).
+// Within a record, Definitions take precedence over Parser.Definitions.
+type Group struct {
+	Head        string      // key in Definitions; "": each argument starts a record (see Record.Arg)
+	Definitions Definitions // template of a record, cloned for each (see Definitions.Clone())
+
+	opts map[string]*Definition // as Parser.opts
+	head *Definition
+}
+
+// A record of Parser.Group, see Result.Records.
+type Record struct {
+	Index       int         // of the head (option or argument) in args
+	Arg         string      // Group.Head "": the argument starting the record
+	Definitions Definitions // Group.Definitions with values of the record
+
+	opts map[string]*Definition // as Parser.opts
+}
+
+func (p *Parser) compileGroup() error {
+	g := p.Group
+	if g == nil {
+		return nil
+	}
+
+	if err := g.Definitions.normalizeOptsCase(p.LongCase); err != nil {
+		return err
+	}
+	g.opts = p.optIndex(g.Definitions)
+
+	if g.Head != "" {
+		if g.head = g.opts[p.optKey(g.Head)]; g.head == nil {
+			return fmt.Errorf("group head %s: %w", optErrorName(g.Head), genericErr{
+				Err: ErrInvalidDefinition, Wrapped: errors.New("Head must be in Group.Definitions"),
+			})
+		}
+	}
+
+	return nil
+}
+
+// Definitions indexed by optKey().
+func (p *Parser) optIndex(defs Definitions) map[string]*Definition {
+	index := make(map[string]*Definition, len(defs))
+	for key, def := range defs {
+		index[p.optKey(key)] = def
+	}
+
+	return index
+}
+
+// Starts a record at tok (head option or argument).
+func (p *Parser) newRecord(res *Result, tok Token) *Record {
+	defs := p.Group.Definitions.Clone()
+	res.Records = append(res.Records, Record{Index: tok.Index, Definitions: defs, opts: p.optIndex(defs)})
+
+	rec := &res.Records[len(res.Records)-1]
+	if tok.Kind == TokenArgument {
+		rec.Arg = tok.Value
+	}
+
+	return rec
+}
+
+// lexer.lookup: the current record, then Parser.Definitions, then Group.Definitions (head, or an option before a head).
+func (p *Parser) groupLookup(res *Result) func(key string) *Definition {
+	return func(key string) *Definition {
+		if n := len(res.Records); n != 0 {
+			if def := res.Records[n-1].opts[key]; def != nil {
+				return def
+			}
+		}
+
+		if def := p.opts[key]; def != nil {
+			return def
+		}
+
+		return p.Group.opts[key]
+	}
+}
+
+// Definition of an option token to parse to, starting a record on Head.
+func (p *Parser) groupDefinition(res *Result, tok Token, def *Definition) (*Definition, error) {
+	template := p.Group.opts[tok.Key]
+	if template == nil {
+		return def, nil // not in group
+	}
+
+	if template == p.Group.head {
+		return p.newRecord(res, tok).opts[tok.Key], nil
+	}
+
+	if def == template { // no record to apply to
+		head := "the first argument"
+		if p.Group.Head != "" {
+			head = optErrorName(p.Group.Head)
+		}
+
+		return nil, fmt.Errorf("%s before %s: %w", tokenContext(tok), head, ErrOptionHasNoDefinition)
+	}
+
+	return def, nil
+}
@@ -0,0 +1,91 @@
+package harg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{
+		Definitions: harg.Definitions{"y": {}, "ss": {Type: harg.String}},
+		Group: &harg.Group{
+			Head: "i",
+			Definitions: harg.Definitions{
+				"i":  {Type: harg.String},
+				"ss": {Type: harg.Duration}, // shadows global within a record
+				"an": {},
+			},
+		},
+	}
+
+	res, err := p.ParseResult([]string{"-y", "-i", "a.mp4", "--ss", "10s", "-i", "b.mp4", "--an", "--ss", "20s", "-y", "out.mp4"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"out.mp4"}, res.Args)
+	require.Len(t, res.Records, 2)
+
+	for i, want := range []struct {
+		index int
+		input string
+		ss    time.Duration
+		an    bool
+	}{
+		{1, "a.mp4", 10 * time.Second, false},
+		{5, "b.mp4", 20 * time.Second, true},
+	} {
+		rec := res.Records[i]
+		require.Equal(t, want.index, rec.Index)
+
+		s, _ := rec.Definitions["i"].String()
+		require.Equal(t, want.input, s)
+		d, _ := rec.Definitions["ss"].Duration()
+		require.Equal(t, want.ss, d)
+		b, _ := rec.Definitions["an"].Bool()
+		require.Equal(t, want.an, b)
+	}
+
+	c, _ := p.Definitions["y"].Count()
+	require.Equal(t, 2, c)
+	require.Equal(t, true, p.Definitions["ss"].Default())
+	require.Equal(t, true, p.Group.Definitions["i"].Default()) // template is not modified
+
+	// global before the first head
+	res, err = p.ParseResult([]string{"--ss=x", "-i", "a"})
+	require.Nil(t, err)
+	s, _ := p.Definitions["ss"].String()
+	require.Equal(t, "x", s)
+
+	// group option before the first head
+	_, err = p.ParseResult([]string{"--an", "-i", "a"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+}
+
+func TestGroupPositional(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{Group: &harg.Group{
+		Definitions: harg.Definitions{"name": {Type: harg.String}},
+	}}
+
+	res, err := p.ParseResult([]string{"a", "--name", "x", "b", "c", "--name=z"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, res.Args)
+
+	var got [][2]string
+	for _, rec := range res.Records {
+		s, _ := rec.Definitions["name"].String()
+		got = append(got, [2]string{rec.Arg, s})
+	}
+	require.Equal(t, [][2]string{{"a", "x"}, {"b", ""}, {"c", "z"}}, got)
+
+	_, err = p.ParseResult([]string{"--name=x", "a"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+	require.ErrorContains(t, err, "before the first argument")
+
+	p = harg.Parser{Group: &harg.Group{Head: "missing", Definitions: harg.Definitions{"name": {}}}}
+	require.ErrorIs(t, p.Compile(), harg.ErrInvalidDefinition)
+}
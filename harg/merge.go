@@ -0,0 +1,137 @@
+package harg
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/maps"
+)
+
+// Deep copy of defs, including parsed values.
+// Keys sharing a Definition (aliases) share the copied Definition.
+func (defs Definitions) Clone() Definitions {
+	if defs == nil {
+		return nil
+	}
+
+	copies := make(map[*Definition]*Definition)
+	clone := make(Definitions, len(defs))
+
+	for key, def := range defs {
+		if def == nil {
+			clone[key] = nil
+			continue
+		}
+
+		c, ok := copies[def]
+		if !ok {
+			c = def.clone()
+			copies[def] = c
+		}
+
+		clone[key] = c
+	}
+
+	return clone
+}
+
+func (def *Definition) clone() *Definition {
+	c := *def // Validate and DefaultValue are shared, DefaultValue is copied on use
+
+	if def.Deprecated != nil {
+		c.Deprecated = maps.Clone(def.Deprecated)
+	}
+
+	for src, layer := range def.layers {
+		if layer == nil {
+			continue
+		}
+
+		c.layers[src] = typeMetaM[def.optionType(layer)].new()
+		c.layers[src].merge(layer)
+	}
+
+	c.resolve()
+	return &c
+}
+
+// How Definitions.Merge() handles a key defined in both (to different Definitions).
+type MergePolicy uint8 // enum:
+const (
+	MergeError    MergePolicy = iota // ErrInvalidDefinition, defs is not modified
+	MergeKeep                        // keep defs' Definition
+	MergeOverride                    // replace with other's Definition
+)
+
+// Adds keys of other to defs. Definitions are not copied, see Clone().
+//
+// Keys of other are compared as defs looks them up: long keys case insensitively, unless normalized with
+// Parser.LongCase CaseSensitive (eg on Parser.Compile()). Short keys are compared as they are.
+// Conflicts are handled per key, including its case variants (eg "Foo" and its normalized alias "foo"):
+// an alias of an overridden key under another name keeps its Definition.
+func (defs Definitions) Merge(other Definitions, policy MergePolicy) error {
+	index := make(map[mergeKey][]string, len(defs)) // keys in defs (eg "Foo" and its normalized alias "foo")
+	for key, def := range defs {
+		mk := newMergeKey(key, def)
+		index[mk] = append(index[mk], key)
+	}
+
+	// keys in defs matching key of other
+	matches := func(key string) (mks []mergeKey, keys []string) {
+		mks = []mergeKey{{key: key}}
+		if !isShortKey(key) {
+			mks = append(mks, mergeKey{key: foldCase(key), folded: true})
+		}
+
+		for _, mk := range mks {
+			keys = append(keys, index[mk]...)
+		}
+		return mks, keys
+	}
+
+	if policy == MergeError {
+		for key, def := range other {
+			_, existing := matches(key)
+			for _, existing := range existing {
+				if defs[existing] != def {
+					return fmt.Errorf("%s: %w", optErrorName(key), genericErr{
+						Err: ErrInvalidDefinition, Wrapped: errors.New("defined in both merged Definitions"),
+					})
+				}
+			}
+		}
+	}
+
+	for key, def := range other {
+		if mks, existing := matches(key); len(existing) != 0 {
+			if policy == MergeKeep {
+				continue
+			}
+
+			for _, existing := range existing {
+				delete(defs, existing)
+			}
+			for _, mk := range mks {
+				delete(index, mk) // other's case variants of key are all added
+			}
+		}
+
+		defs[key] = def
+	}
+
+	return nil
+}
+
+// Key as looked up in Definitions, with the LongCase its Definition was normalized with (default: case insensitive).
+type mergeKey struct {
+	key    string
+	folded bool
+}
+
+func newMergeKey(key string, def *Definition) mergeKey {
+	if isShortKey(key) || def == nil || def.longCase == CaseSensitive {
+		return mergeKey{key: key}
+	}
+
+	return mergeKey{key: foldCase(key), folded: true}
+}
@@ -0,0 +1,103 @@
+package harg_test
+
+import (
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"tag":  {Type: harg.String, DefaultValue: "d"},
+		"v":    {},
+		"name": {Type: harg.String},
+	}
+	require.Nil(t, defs.Alias("t", "tag"))
+	require.Nil(t, defs.Deprecate("t", harg.Deprecation{Replacement: "--tag"}))
+
+	_, _, err := defs.Parse([]string{"--tag=a", "-v"}, nil)
+	require.Nil(t, err)
+
+	clone := defs.Clone()
+	require.Len(t, clone, len(defs))
+	require.Same(t, clone["t"], clone["tag"]) // alias group preserved
+	require.NotSame(t, clone["tag"], defs["tag"])
+
+	sl, _ := clone["tag"].SlString()
+	require.Equal(t, []string{"a"}, sl)
+	d, _ := clone["tag"].Layer(harg.SourceDefault)
+	require.Equal(t, []string{"d"}, d)
+	c, _ := clone["v"].Count()
+	require.Equal(t, 1, c)
+	require.Equal(t, true, clone["name"].Default())
+
+	// independent
+	_, _, err = clone.Parse([]string{"-t", "b"}, nil)
+	require.Nil(t, err)
+	sl, _ = clone["tag"].SlString()
+	require.Equal(t, []string{"a", "b"}, sl)
+	sl, _ = defs["tag"].SlString()
+	require.Equal(t, []string{"a"}, sl)
+
+	clone["t"].Deprecated["tag"] = harg.Deprecation{}
+	require.Len(t, defs["tag"].Deprecated, 1)
+
+	require.Nil(t, harg.Definitions(nil).Clone())
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	shared := &harg.Definition{}
+	newDefs := func() (harg.Definitions, harg.Definitions) {
+		return harg.Definitions{"v": shared, "name": {Type: harg.String}},
+			harg.Definitions{"v": shared, "name": {Type: harg.Int}, "port": {Type: harg.Int}}
+	}
+
+	defs, other := newDefs()
+	before := defs["name"]
+	require.ErrorIs(t, defs.Merge(other, harg.MergeError), harg.ErrInvalidDefinition)
+	require.Len(t, defs, 2) // not modified
+	require.Same(t, before, defs["name"])
+
+	defs, other = newDefs()
+	require.Nil(t, defs.Merge(other, harg.MergeKeep))
+	require.Len(t, defs, 3)
+	require.Equal(t, harg.String, defs["name"].Type)
+	require.Same(t, other["port"], defs["port"])
+
+	defs, other = newDefs()
+	require.Nil(t, defs.Merge(other, harg.MergeOverride))
+	require.Equal(t, harg.Int, defs["name"].Type)
+
+	// same Definition is not a conflict
+	defs, _ = newDefs()
+	require.Nil(t, defs.Merge(harg.Definitions{"v": shared}, harg.MergeError))
+
+	// long keys are case insensitive, short keys are not
+	defs = harg.Definitions{"Foo": {}, "v": shared}
+	require.ErrorIs(t, defs.Merge(harg.Definitions{"foo": {}}, harg.MergeError), harg.ErrInvalidDefinition)
+	require.Nil(t, defs.Merge(harg.Definitions{"V": {}}, harg.MergeError))
+
+	defs = harg.Definitions{"Foo": {Type: harg.String}}
+	require.Nil(t, defs.Merge(harg.Definitions{"foo": {Type: harg.Int}}, harg.MergeKeep))
+	require.Equal(t, harg.Definitions{"Foo": {Type: harg.String}}, defs)
+
+	require.Nil(t, defs.Merge(harg.Definitions{"foo": {Type: harg.Int}}, harg.MergeOverride))
+	require.Equal(t, harg.Definitions{"foo": {Type: harg.Int}}, defs)
+
+	// parsed, with the normalized alias "foo" of "Foo"
+	for i := 0; i < 20; i++ {
+		defs = harg.Definitions{"Foo": {Type: harg.String}}
+		_, _, err := defs.Parse([]string{"--foo=a"}, nil)
+		require.Nil(t, err)
+		require.Len(t, defs, 2)
+
+		override := &harg.Definition{Type: harg.Int}
+		require.Nil(t, defs.Merge(harg.Definitions{"FOO": override}, harg.MergeOverride))
+		require.Equal(t, harg.Definitions{"FOO": override}, defs)
+	}
+}
@@ -4,16 +4,18 @@ import (
 	"time"
 )
 
-// Whether definition's value is a default value (was it set via Parse())
+// Whether definition's value is a default value (not set via Parse(), ParseEnv() or ParseConfig())
 func (def *Definition) Default() bool {
 	if def == nil {
 		return false
 	}
-	return def.parsed == nil
+
+	src, ok := def.Source()
+	return !ok || src == SourceDefault
 }
 
 func (def *Definition) SlAny() (v any, ok bool) {
-	switch def.Type {
+	switch def.valueType() {
 	case Bool:
 		return def.SlBool()
 	case String:
@@ -36,7 +38,7 @@ func (def *Definition) SlAny() (v any, ok bool) {
 }
 
 func (def *Definition) Any() (v any, ok bool) {
-	switch def.Type {
+	switch def.valueType() {
 	case Bool:
 		return def.Bool()
 	case String:
@@ -63,7 +65,16 @@ func (def *Definition) IsBool() bool {
 	if def == nil {
 		return false
 	}
-	return def.Type == Bool
+	return def.valueType() == Bool
+}
+
+// Type of the resolved value, AlsoBool may resolve to Bool.
+func (def *Definition) valueType() Type {
+	v := def.value()
+	if v == nil {
+		return def.Type
+	}
+	return def.optionType(v)
 }
 
 // Count of consecutive true values read from right/last
@@ -99,11 +110,12 @@ func (o optBool) contents() any {
 
 func (def *Definition) SlBool() ([]bool, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Bool {
+	v := def.value()
+	if v == nil || def.optionType(v) != Bool {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]bool), true
+	return v.contents().([]bool), true
 }
 
 func (def *Definition) Bool() (v bool, ok bool) {
@@ -122,11 +134,12 @@ func (o optString) contents() any {
 
 func (def *Definition) SlString() ([]string, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != String {
+	v := def.value()
+	if v == nil || def.optionType(v) != String {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]string), true
+	return v.contents().([]string), true
 }
 
 func (def *Definition) String() (v string, ok bool) {
@@ -145,11 +158,12 @@ func (o optInt) contents() any {
 
 func (def *Definition) SlInt() ([]int, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Int {
+	v := def.value()
+	if v == nil || def.optionType(v) != Int {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]int), true
+	return v.contents().([]int), true
 }
 
 func (def *Definition) Int() (v int, ok bool) {
@@ -168,11 +182,12 @@ func (o optInt64) contents() any {
 
 func (def *Definition) SlInt64() ([]int64, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Int64 {
+	v := def.value()
+	if v == nil || def.optionType(v) != Int64 {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]int64), true
+	return v.contents().([]int64), true
 }
 
 func (def *Definition) Int64() (v int64, ok bool) {
@@ -191,11 +206,12 @@ func (o optUint) contents() any {
 
 func (def *Definition) SlUint() ([]uint, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Uint {
+	v := def.value()
+	if v == nil || def.optionType(v) != Uint {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]uint), true
+	return v.contents().([]uint), true
 }
 
 func (def *Definition) Uint() (v uint, ok bool) {
@@ -214,11 +230,12 @@ func (o optUint64) contents() any {
 
 func (def *Definition) SlUint64() ([]uint64, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Uint64 {
+	v := def.value()
+	if v == nil || def.optionType(v) != Uint64 {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]uint64), true
+	return v.contents().([]uint64), true
 }
 
 func (def *Definition) Uint64() (v uint64, ok bool) {
@@ -237,11 +254,12 @@ func (o optFloat64) contents() any {
 
 func (def *Definition) SlFloat64() ([]float64, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Float64 {
+	v := def.value()
+	if v == nil || def.optionType(v) != Float64 {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]float64), true
+	return v.contents().([]float64), true
 }
 
 func (def *Definition) Float64() (v float64, ok bool) {
@@ -260,11 +278,12 @@ func (o optDuration) contents() any {
 
 func (def *Definition) SlDuration() ([]time.Duration, bool) {
 	// not seen/parsed or mismatched type
-	if def.Default() || def.Type != Duration {
+	v := def.value()
+	if v == nil || def.optionType(v) != Duration {
 		return nil, false
 	}
 
-	return def.parsed.contents().([]time.Duration), true
+	return v.contents().([]time.Duration), true
 }
 
 func (def *Definition) Duration() (v time.Duration, ok bool) {
@@ -3,52 +3,130 @@ package harg
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
-func (def *Definition) parseValue(value string, errContext func() string) error { // errContext provided
-	// restore
-	if def.AlsoBool && def.originalType != Bool {
-		def.parsed, def.Type = nil, def.originalType
+func (def *Definition) parseValue(value string, src Source, errContext func() string) error { // errContext provided
+	return def.parseValueExpand(value, def.Expand, src, errContext)
+}
+
+// parseValue(), expanding with lookup (nil: value is literal, eg read from a file).
+func (def *Definition) parseValueExpand(value string, lookup func(key string) (string, bool), src Source, errContext func() string) error {
+	// values are kept only if all of them are valid
+	added := typeMetaM[def.Type].new()
+
+	values := []string{value}
+	if def.Slice == SliceSplit {
+		values = splitCSV(value)
+	}
+
+	for _, value := range values {
+		if lookup != nil {
+			value = expand(value, lookup)
+		}
+
+		if err := added.add(value); err != nil {
+			return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
+				Err:     ErrIncompatibleValue,
+				Wrapped: def.redactErr(err),
+			})
+		}
+
+		if err := def.validate(added, errContext); err != nil {
+			return err
+		}
 	}
 
-	// initialize option interface
-	if def.parsed == nil {
-		def.parsed = typeMetaM[def.Type].new()
+	// AlsoBool: bools before a value are ignored
+	if layer := def.layers[src]; layer != nil && def.optionType(layer) == def.Type {
+		layer.merge(added)
+	} else {
+		def.layers[src] = added
 	}
 
-	if err := def.parsed.add(value); err != nil {
+	def.resolve()
+	return nil
+}
+
+// SliceReplace: an occurrence (option, environment variable, config key) replaces earlier values of src,
+// as a whole (including EnvCSV, SliceSplit or config list values).
+func (def *Definition) replaceLayer(src Source) {
+	if def.Slice == SliceReplace && def.layers[src] != nil {
+		def.layers[src] = nil
+		def.resolve()
+	}
+}
+
+// EnvCSV and SliceSplit
+func splitCSV(s string) []string {
+	return strings.Split(s, ",")
+}
+
+func (def *Definition) parseBoolValue(val bool, src Source, errContext func() string) error {
+	// defs.normalize(): actual Type == Bool can never be AlsoBool
+
+	layer, isBool := def.layers[src].(*optBool)
+	if !isBool && def.layers[src] != nil {
 		return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
 			Err:     ErrIncompatibleValue,
-			Wrapped: err,
+			Wrapped: errors.New("AlsoBool must not have a Bool value after non-Bool value"),
 		})
 	}
 
+	added := &optBool{}
+	added.addT(val)
+	if err := def.validate(added, errContext); err != nil {
+		return err // not kept
+	}
+
+	if layer != nil {
+		layer.merge(added)
+	} else {
+		def.layers[src] = added
+	}
+
+	def.resolve()
 	return nil
 }
 
-func (def *Definition) parseBoolValue(val bool, errContext func() string) error {
-	// defs.normalize(): actual Type == Bool can never be AlsoBool
+// Definition.Validate for the last value added to layer
+func (def *Definition) validate(layer option, errContext func() string) error {
+	if def.Enum == nil && def.Validate == nil {
+		return nil
+	}
 
-	if def.parsed == nil {
-		def.parsed = typeMetaM[Bool].new()
+	v := layer.last()
+	if def.Enum != nil && def.optionType(layer) == def.Type && !def.inEnum(v) {
+		return fmt.Errorf("validating %s: %w", errContext(), genericErr{
+			Err:     ErrIncompatibleValue,
+			Wrapped: def.redactErr(fmt.Errorf("%v is not one of %v", v, def.Enum)),
+		})
+	}
 
-		if def.AlsoBool {
-			def.originalType = def.Type
-			def.Type = Bool
-		}
+	if def.Validate == nil {
+		return nil
 	}
 
-	if def.Type != Bool {
-		return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
+	if err := def.Validate(v); err != nil {
+		return fmt.Errorf("validating %s: %w", errContext(), genericErr{
 			Err:     ErrIncompatibleValue,
-			Wrapped: errors.New("AlsoBool must not have a Bool value after non-Bool value"),
+			Wrapped: def.redactErr(err),
 		})
 	}
 
-	def.parsed.(*optBool).addT(val)
 	return nil
 }
 
 func (o *optBool) addT(v bool) {
 	o.value = append(o.value, v)
 }
+
+func (def *Definition) inEnum(v any) bool {
+	for _, e := range def.Enum {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package harg_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceMode(t *testing.T) {
+	kEnv := "HARG_TEST_SLICE_MODE"
+	require.Nil(t, os.Setenv(kEnv, "e,f"))
+
+	for mode, want := range map[harg.SliceMode][]string{
+		harg.SliceAppend:  {"a", "b,c"},
+		harg.SliceReplace: {"b,c"},
+		harg.SliceSplit:   {"a", "b", "c"},
+	} {
+		defs := harg.Definitions{
+			"tag": {Type: harg.String, Slice: mode},
+		}
+
+		_, _, err := defs.Parse([]string{"--tag", "a", "--tag=b,c"}, nil)
+		require.Nil(t, err)
+
+		sl, _ := defs["tag"].SlString()
+		require.Equal(t, want, sl, mode)
+	}
+
+	// environment is split as with EnvCSV
+	for mode, want := range map[harg.SliceMode][]string{
+		harg.SliceAppend:  {"e,f"},
+		harg.SliceReplace: {"e,f"},
+		harg.SliceSplit:   {"e", "f"},
+	} {
+		defs := harg.Definitions{kEnv: {Type: harg.String, Slice: mode}}
+		require.Nil(t, defs.ParseEnv())
+
+		sl, _ := defs[kEnv].SlString()
+		require.Equal(t, want, sl, mode)
+	}
+
+	// EnvCSV: a variable replaces as a whole
+	defs := harg.Definitions{kEnv: {Type: harg.String, Slice: harg.SliceReplace, EnvCSV: true}}
+	require.Nil(t, defs.ParseEnv())
+	require.Nil(t, defs.ParseEnv())
+	sl, _ := defs[kEnv].SlString()
+	require.Equal(t, []string{"e", "f"}, sl)
+
+	// config lists are repeated values, a key replaces as a whole
+	defs = harg.Definitions{"tag": {Type: harg.String, Slice: harg.SliceReplace}}
+	require.Nil(t, defs.ParseConfig(strings.NewReader(`{"tag": ["a", "b"]}`), harg.JSON))
+	require.Nil(t, defs.ParseConfig(strings.NewReader(`{"tag": ["c", "d"]}`), harg.JSON))
+	sl, _ = defs["tag"].SlString()
+	require.Equal(t, []string{"c", "d"}, sl)
+
+	// bools
+	defs = harg.Definitions{"v": {Slice: harg.SliceReplace}}
+	_, _, err := defs.Parse([]string{"-vvv"}, nil)
+	require.Nil(t, err)
+	c, _ := defs["v"].Count()
+	require.Equal(t, 1, c)
+}
+
+func TestValidate(t *testing.T) {
+	errNegative := errors.New("must not be negative")
+	positive := func(v any) error {
+		if v.(int) < 0 {
+			return errNegative
+		}
+		return nil
+	}
+
+	defs := harg.Definitions{"n": {Type: harg.Int, Validate: positive}}
+	_, _, err := defs.Parse([]string{"-n", "1", "-n", "2"}, nil)
+	require.Nil(t, err)
+	sl, _ := defs["n"].SlInt()
+	require.Equal(t, []int{1, 2}, sl)
+
+	defs = harg.Definitions{"n": {Type: harg.Int, Validate: positive}}
+	_, _, err = defs.Parse([]string{"-n", "1", "-n", "-2"}, nil)
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+	require.ErrorIs(t, err, errNegative)
+	require.ErrorContains(t, err, "argument 2")
+	sl, _ = defs["n"].SlInt() // rejected value is not kept
+	require.Equal(t, []int{1}, sl)
+
+	defs = harg.Definitions{"n": {Type: harg.Int, Slice: harg.SliceSplit, Validate: positive, DefaultValue: 3}}
+	_, _, err = defs.Parse([]string{"-n", "1,-2"}, nil)
+	require.ErrorIs(t, err, errNegative)
+	src, _ := defs["n"].Source() // nor is the rest of the occurrence
+	require.Equal(t, harg.SourceDefault, src)
+
+	// SliceSplit: each value
+	var got []any
+	defs = harg.Definitions{"tag": {Type: harg.String, Slice: harg.SliceSplit, Validate: func(v any) error {
+		got = append(got, v)
+		return nil
+	}}}
+	_, _, err = defs.Parse([]string{"--tag=a,b"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, []any{"a", "b"}, got)
+
+	// AlsoBool bools
+	got = nil
+	defs = harg.Definitions{"color": {Type: harg.String, AlsoBool: true, Validate: func(v any) error {
+		got = append(got, v)
+		return nil
+	}}}
+	_, _, err = defs.Parse([]string{"--color", "---color", "--color=auto"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, []any{true, false, "auto"}, got)
+
+	defs = harg.Definitions{"color": {Type: harg.String, AlsoBool: true, Validate: func(v any) error {
+		if v == false {
+			return errNegative
+		}
+		return nil
+	}}}
+	_, _, err = defs.Parse([]string{"--color", "---color"}, nil)
+	require.ErrorIs(t, err, errNegative)
+	bl, _ := defs["color"].SlBool()
+	require.Equal(t, []bool{true}, bl)
+
+	// environment
+	kEnv := "HARG_TEST_VALIDATE"
+	require.Nil(t, os.Setenv(kEnv, "-1"))
+	defs = harg.Definitions{kEnv: {Type: harg.Int, Validate: positive}}
+	require.ErrorIs(t, defs.ParseEnv(), errNegative)
+	require.Equal(t, true, defs[kEnv].Default())
+}
@@ -8,6 +8,11 @@ import (
 type option interface {
 	contents() any           // resolved with option.Sl
 	add(rawOpt string) error // string: type name (to use in error)
+	merge(option)            // appends values of the same type
+	last() any               // most recently added value (T)
+
+	setDefault(v any) (empty, ok bool) // v: T or []T
+	format() []string                  // values as accepted by add()
 }
 
 type Type uint8 // enum:
@@ -42,6 +47,32 @@ func (t Type) String() string {
 	return typeMetaM[t].name
 }
 
+func setDefault[T any](dst *[]T, v any) (empty, ok bool) {
+	switch v := v.(type) {
+	case T:
+		*dst = []T{v}
+	case []T:
+		*dst = append([]T(nil), v...) // don't share with definer
+	default:
+		return false, false
+	}
+
+	return len(*dst) == 0, true
+}
+
+func last[T any](sl []T) any {
+	return sl[len(sl)-1]
+}
+
+func formatEach[T any](sl []T, format func(T) string) []string {
+	s := make([]string, len(sl))
+	for i, v := range sl {
+		s[i] = format(v)
+	}
+
+	return s
+}
+
 // bool / count
 
 type (
@@ -60,6 +91,22 @@ func (o *optBool) add(s string) error {
 	return nil
 }
 
+func (o *optBool) merge(src option) {
+	o.value = append(o.value, src.contents().([]bool)...)
+}
+
+func (o *optBool) last() any {
+	return last(o.value)
+}
+
+func (o *optBool) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optBool) format() []string {
+	return formatEach(o.value, strconv.FormatBool)
+}
+
 // string
 
 type optString struct {
@@ -71,6 +118,22 @@ func (o *optString) add(s string) error {
 	return nil
 }
 
+func (o *optString) merge(src option) {
+	o.value = append(o.value, src.contents().([]string)...)
+}
+
+func (o *optString) last() any {
+	return last(o.value)
+}
+
+func (o *optString) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optString) format() []string {
+	return formatEach(o.value, func(v string) string { return v })
+}
+
 // int
 
 type optInt struct {
@@ -87,6 +150,22 @@ func (o *optInt) add(s string) error {
 	return err
 }
 
+func (o *optInt) merge(src option) {
+	o.value = append(o.value, src.contents().([]int)...)
+}
+
+func (o *optInt) last() any {
+	return last(o.value)
+}
+
+func (o *optInt) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optInt) format() []string {
+	return formatEach(o.value, strconv.Itoa)
+}
+
 // int64
 
 type optInt64 struct {
@@ -103,6 +182,22 @@ func (o *optInt64) add(s string) error {
 	return err
 }
 
+func (o *optInt64) merge(src option) {
+	o.value = append(o.value, src.contents().([]int64)...)
+}
+
+func (o *optInt64) last() any {
+	return last(o.value)
+}
+
+func (o *optInt64) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optInt64) format() []string {
+	return formatEach(o.value, func(v int64) string { return strconv.FormatInt(v, 10) })
+}
+
 // uint
 
 type optUint struct {
@@ -119,6 +214,22 @@ func (o *optUint) add(s string) error {
 	return err
 }
 
+func (o *optUint) merge(src option) {
+	o.value = append(o.value, src.contents().([]uint)...)
+}
+
+func (o *optUint) last() any {
+	return last(o.value)
+}
+
+func (o *optUint) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optUint) format() []string {
+	return formatEach(o.value, func(v uint) string { return strconv.FormatUint(uint64(v), 10) })
+}
+
 // uint64
 
 type optUint64 struct {
@@ -135,6 +246,22 @@ func (o *optUint64) add(s string) error {
 	return err
 }
 
+func (o *optUint64) merge(src option) {
+	o.value = append(o.value, src.contents().([]uint64)...)
+}
+
+func (o *optUint64) last() any {
+	return last(o.value)
+}
+
+func (o *optUint64) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optUint64) format() []string {
+	return formatEach(o.value, func(v uint64) string { return strconv.FormatUint(v, 10) })
+}
+
 // float64
 
 type optFloat64 struct {
@@ -151,6 +278,22 @@ func (o *optFloat64) add(s string) error {
 	return err
 }
 
+func (o *optFloat64) merge(src option) {
+	o.value = append(o.value, src.contents().([]float64)...)
+}
+
+func (o *optFloat64) last() any {
+	return last(o.value)
+}
+
+func (o *optFloat64) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optFloat64) format() []string {
+	return formatEach(o.value, func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) })
+}
+
 // duration
 
 type optDuration struct {
@@ -167,8 +310,24 @@ func (o *optDuration) add(s string) error {
 	return err
 }
 
+func (o *optDuration) merge(src option) {
+	o.value = append(o.value, src.contents().([]time.Duration)...)
+}
+
+func (o *optDuration) last() any {
+	return last(o.value)
+}
+
+func (o *optDuration) setDefault(v any) (empty, ok bool) {
+	return setDefault(&o.value, v)
+}
+
+func (o *optDuration) format() []string {
+	return formatEach(o.value, time.Duration.String)
+}
+
 // TODO: more Types
-// add to: Types enum; option_set (2); option_get (3)
+// add to: Types enum; option_set (6); option_get (3)
 //
 // timestamp
 // ip
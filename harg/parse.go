@@ -2,9 +2,6 @@ package harg
 
 import (
 	"errors"
-	"fmt"
-	"os"
-	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -14,6 +11,8 @@ var (
 	// end user (runtime) error
 	ErrOptionHasNoDefinition = errors.New("option has no definition") // or invalid Alias() target
 	ErrIncompatibleValue     = errors.New("incompatible value")       // eg strconv.Atoi("this is not a number")
+	ErrMissingArgument       = errors.New("missing argument")         // see Positional, Definition.Required
+	ErrExtraArgument         = errors.New("unexpected argument")      // see Positional
 
 	// library user error; always returned on Parse()
 	ErrInvalidDefinition = errors.New("invalid definition")
@@ -38,62 +37,14 @@ func (defs *Definitions) Parse(
 	chokeReturn []string, // see above
 	err error, // see above var() for possible errors
 ) {
-	if len(args) == 0 {
-		return nil, nil, nil
-	}
-
-	if err := defs.normalizeOpts(); err != nil {
-		return nil, nil, err
-	}
-
-	chokeM := chokeIndex(chokes)
-
-	for {
-		var skipNext bool
-
-		switch argumentKind(args[0]) {
-		case argument:
-			if _, isChoke := chokeM[strings.ToLower(args[0])]; isChoke {
-				return parsed, args, nil
-			}
-
-			parsed = append(parsed, args[0])
-
-		case argumentDivider:
-			parsed = append(parsed, args[1:]...)
-
-			return parsed, nil, nil
-
-		case shortOption:
-			skipNext, err = defs.parseShortOption(args) // len(a) > 1 or parseShortOption panics
-			if err != nil {
-				return nil, nil, err
-			}
-
-		case longOption:
-			skipNext, err = defs.parseLongOption(args) // len(a) > 2 or parseLongOption panics
-			if err != nil {
-				return nil, nil, err
-			}
-		}
-
-		if len(args) == 1 {
-			break
-		}
-
-		if skipNext {
-			if len(args) == 2 {
-				break
-			}
-
-			args = args[2:]
-			continue
-		}
-
-		args = args[1:]
-	}
+	p := Parser{Definitions: *defs, Chokes: chokes}
+	return p.Parse(args)
+}
 
-	return parsed, nil, nil
+// Parse(), additionally telling apart arguments after the divider ("--"). See Result.
+func (defs *Definitions) ParseResult(args []string, chokes []string) (Result, error) {
+	p := Parser{Definitions: *defs, Chokes: chokes}
+	return p.ParseResult(args)
 }
 
 type argumentKindT uint8 // enum:
@@ -133,54 +84,21 @@ func argumentKind(arg string) argumentKindT {
 //
 // All definitions will be transformed to uppercase. Spaces are replaced with underscores.
 func (defs *Definitions) ParseEnv() error {
-	if err := defs.normalizeEnv(); err != nil {
-		return err
-	}
-
-	for _, env := range os.Environ() {
-		key, rawVal := parseEnviron(env)
-		errContext := func() string { return fmt.Sprintf("environment %s", key) }
-
-		def, ok := (*defs)[key]
-		if !ok {
-			continue // ignore unrecognized env
-		}
-
-		vals := []string{rawVal}
-		if def.EnvCSV {
-			vals = strings.Split(rawVal, ",")
-		}
-
-		for _, val := range vals {
-			if def.AlsoBool {
-				boolVal, err := strconv.ParseBool(val)
-				if err == nil {
-					def.parseBoolValue(boolVal, errContext)
-				}
-			}
-
-			if err := def.parseValue(val, errContext); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	p := Parser{Env: *defs}
+	return p.ParseEnv()
 }
 
 func parseEnviron(s string) (key, val string) {
 	key, val, _ = strings.Cut(s, "=")
-	key = strings.ToUpper(key)
-
 	return
 }
 
-// converts slice to lowercase empty-valued map
-func chokeIndex(s []string) map[string]struct{} {
+// converts slice to empty-valued map of normalized keys
+func chokeIndex(s []string, chokeCase CaseMode) map[string]struct{} {
 	index := make(map[string]struct{})
 
 	for _, str := range s {
-		index[strings.ToLower(str)] = struct{}{}
+		index[chokeCase.normalize(str)] = struct{}{}
 	}
 
 	return index
@@ -0,0 +1,169 @@
+package harg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Decodes a configuration document to (nested) key-value pairs.
+type ConfigFormat func(r io.Reader) (map[string]any, error)
+
+var (
+	JSON ConfigFormat = decodeJSON
+	TOML ConfigFormat = decodeTOML
+
+	// Used by ParseConfigFile(), key: lowercase file extension.
+	ConfigFormats = map[string]ConfigFormat{
+		".json": JSON,
+		".toml": TOML,
+	}
+)
+
+func decodeJSON(r io.Reader) (map[string]any, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber() // keep integers as written, not float64
+
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func decodeTOML(r io.Reader) (map[string]any, error) {
+	var m map[string]any
+	if _, err := toml.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Parses Definitions from a configuration file, format is chosen by extension (see ConfigFormats).
+func (defs *Definitions) ParseConfigFile(name string) error {
+	p := Parser{Definitions: *defs}
+	return p.ParseConfigFile(name)
+}
+
+// See Definitions.ParseConfigFile(), keys are matched as Parser.LongCase.
+func (p *Parser) ParseConfigFile(name string) error {
+	format, ok := ConfigFormats[strings.ToLower(filepath.Ext(name))]
+	if !ok {
+		return fmt.Errorf("config file %s: unknown format", name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+
+	return p.ParseConfig(f, format)
+}
+
+// Parses Definitions from a configuration document. Keys are long option names.
+//
+// Nested keys are joined with "-": `{"log": {"level": "debug"}}` sets `log-level`.
+// Lists are parsed as repeated values. Values are converted as with Parse().
+func (defs *Definitions) ParseConfig(r io.Reader, format ConfigFormat) error {
+	p := Parser{Definitions: *defs}
+	return p.ParseConfig(r, format)
+}
+
+// See Definitions.ParseConfig(), keys are matched as Parser.LongCase.
+func (p *Parser) ParseConfig(r io.Reader, format ConfigFormat) error {
+	if err := p.Compile(); err != nil {
+		return err
+	}
+
+	m, err := format(r)
+	if err != nil {
+		return fmt.Errorf("decoding config: %w", err)
+	}
+
+	return p.parseConfigMap("", m)
+}
+
+func (p *Parser) parseConfigMap(prefix string, m map[string]any) error {
+	keys := maps.Keys(m)
+	slices.Sort(keys) // deterministic AlsoBool ordering and errors
+
+	for _, name := range keys {
+		key, val := name, m[name]
+		if prefix != "" {
+			key = prefix + "-" + name
+		}
+
+		if nested, ok := val.(map[string]any); ok {
+			if err := p.parseConfigMap(key, nested); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		def, ok := p.opts[p.optKey(key)]
+		if !ok {
+			return fmt.Errorf("config: %s: %w", optErrorName(key), ErrOptionHasNoDefinition)
+		}
+
+		errContext := func() string { return fmt.Sprintf("config key %s", key) }
+
+		vals, ok := val.([]any)
+		if !ok {
+			vals = []any{val}
+		}
+
+		def.replaceLayer(SourceConfig)
+
+		for _, val := range vals {
+			if err := def.parseConfigValue(val, errContext); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (def *Definition) parseConfigValue(val any, errContext func() string) error {
+	var s string
+
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case bool:
+		if def.Type == Bool || def.AlsoBool {
+			return def.parseBoolValue(v, SourceConfig, errContext)
+		}
+		s = strconv.FormatBool(v)
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
+			Err:     ErrIncompatibleValue,
+			Wrapped: fmt.Errorf("unsupported config value type %T", val),
+		})
+	}
+
+	return def.parseValue(s, SourceConfig, errContext)
+}
@@ -0,0 +1,111 @@
+package harg_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		format harg.ConfigFormat
+		doc    string
+	}{
+		"json": {harg.JSON, `{
+			"name": "hello",
+			"Count": 3,
+			"verbose": true,
+			"tag": ["a", "b"],
+			"log": {"level": "debug", "timeout": "5s"},
+			"alsobool": true
+		}`},
+		"toml": {harg.TOML, `
+			name = "hello"
+			Count = 3
+			verbose = true
+			tag = ["a", "b"]
+			alsobool = true
+
+			[log]
+			level = "debug"
+			timeout = "5s"
+		`},
+	} {
+		defs := harg.Definitions{
+			"name":        {Type: harg.String},
+			"count":       {Type: harg.Int},
+			"verbose":     {},
+			"tag":         {Type: harg.String},
+			"log-level":   {Type: harg.String},
+			"log-timeout": {Type: harg.Duration},
+			"alsobool":    {Type: harg.String, AlsoBool: true},
+		}
+
+		require.Nil(t, defs.ParseConfig(strings.NewReader(test.doc), test.format), name)
+
+		s, _ := defs["name"].String()
+		require.Equal(t, "hello", s, name)
+
+		i, _ := defs["count"].Int()
+		require.Equal(t, 3, i, name)
+
+		b, _ := defs["verbose"].Bool()
+		require.Equal(t, true, b, name)
+
+		sl, _ := defs["tag"].SlString()
+		require.Equal(t, []string{"a", "b"}, sl, name)
+
+		s, _ = defs["log-level"].String()
+		require.Equal(t, "debug", s, name)
+
+		d, _ := defs["log-timeout"].Duration()
+		require.Equal(t, 5*time.Second, d, name)
+
+		require.Equal(t, true, defs["alsobool"].IsBool(), name)
+	}
+}
+
+func TestParseConfigError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		doc   string
+		errIs error
+	}{
+		{doc: `{"nodef": 1}`, errIs: harg.ErrOptionHasNoDefinition},
+		{doc: `{"int": "one"}`, errIs: harg.ErrIncompatibleValue},
+		{doc: `{"int": [[1]]}`, errIs: harg.ErrIncompatibleValue},
+	} {
+		defs := harg.Definitions{
+			"int": {Type: harg.Int},
+		}
+
+		require.ErrorIs(t, defs.ParseConfig(strings.NewReader(test.doc), harg.JSON), test.errIs)
+	}
+}
+
+func TestParseConfigFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "config.TOML")
+	require.Nil(t, os.WriteFile(name, []byte(`foo = "bar"`), 0o600))
+
+	defs := harg.Definitions{
+		"foo": {Type: harg.String},
+	}
+	require.Nil(t, defs.ParseConfigFile(name))
+
+	s, ok := defs["foo"].String()
+	require.Equal(t, true, ok)
+	require.Equal(t, "bar", s)
+
+	require.NotNil(t, defs.ParseConfigFile(filepath.Join(dir, "config.ini")))
+}
@@ -6,111 +6,59 @@ import (
 	"strings"
 )
 
-// long option Bool (--foo) (---foo) or (--foo=value) (--foo) (--foo value)
-//
-// caller should ensure len(args[i]) > 3; and defs.checkDefs()
-func (defs *Definitions) parseLongOption(args []string) (consumedNext bool, _ error) {
-	argName := strings.ToLower(args[0][2:]) // [2:]: remove prefix "--"
-	if argName == "" {
-		panic("parseLongOption caller did not ensure len(args[0]) > 2")
+// Parses a short or long option token to its definition, expanding values with lookup (see Definition.Expand).
+func parseOption(tok Token, def *Definition, lookup func(key string) (string, bool)) error {
+	prefix := "-"
+	if tok.Kind == TokenLong {
+		prefix = "---"
 	}
+	errContext := func() string { return tokenContext(tok) }
 
-	key, value, valueFound := strings.Cut(argName, "=")
-	errContext := func() string { return fmt.Sprintf("long option %s", key) }
-
-	key, negateBool := trimPrefix(key, "-") // ---foo (three dashes negate)
-
-	def, err := defs.get(key)
-	if err != nil {
-		return false, err
+	if def == nil {
+		return fmt.Errorf("%s: %w", optErrorName(tok.Key), ErrOptionHasNoDefinition)
 	}
 
-	if negateBool {
+	if tok.Negated {
 		if !(def.Type == Bool || def.AlsoBool) {
-			return false, fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
+			return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
 				Err:     ErrIncompatibleValue,
-				Wrapped: errors.New("only Bool option definitions can use negating prefix '---'"),
+				Wrapped: fmt.Errorf("only Bool option definitions can use negating prefix '%s'", prefix),
 			})
 		}
 
-		if valueFound {
-			return false, fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
+		if tok.HasValue {
+			return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
 				Err:     ErrIncompatibleValue,
 				Wrapped: errors.New("negating prefix '---' can't have any value (---option=value)"),
 			})
 		}
 	}
 
-	// Bool has no lookahead, default = true
-	if value == "" && (def.Type == Bool || def.AlsoBool) {
-		return false, def.parseBoolValue(!negateBool, errContext)
+	if !tok.HasValue && !tok.Negated && def.Type != Bool && !def.AlsoBool {
+		return fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
+			Err:     ErrIncompatibleValue,
+			Wrapped: errors.New("missing value (--option=value)"),
+		})
 	}
 
-	if !valueFound && len(args) > 1 {
-		consumedNext, value = lookAheadValue(args[1])
-	}
-
-	return consumedNext, def.parseValue(value, errContext)
-}
+	def.replaceLayer(SourceArgs)
 
-// short option(s) (-f) (-fff) (-fb) (-fbvalue) (-fb value) (--n) (-y-ny)
-//
-// caller should ensure len(args[i]) >= 2; and defs.checkDefs()
-func (defs *Definitions) parseShortOption(args []string) (consumedNext bool, _ error) {
-	argRune := []rune(args[0][1:]) // [1:]: remove prefix "-"
-	if len(argRune) == 0 {
-		panic("parseShortOption caller did not ensure len(args[0]) > 1")
+	// Bool default = true; AlsoBool with "=" is always a value
+	if (tok.Value == "" && def.Type == Bool) || (!tok.HasValue && def.AlsoBool) {
+		return def.parseBoolValue(!tok.Negated, SourceArgs, errContext)
 	}
 
-	// loop through clustered (-abc = -a -b -c) options
-	var negateNext bool
-	for optI, opt := range argRune {
-
-		value := ""
-		key := string(opt)
-		errContext := func() string { return fmt.Sprintf("short option %s", key) }
-
-		if key == "-" {
-			// short option prefix "-" negates
-			negateNext = true
-			continue
-		}
-
-		def, err := defs.get(key)
-		if err != nil {
-			return false, err
-		}
-
-		if def.Type == Bool || def.AlsoBool {
-			err := def.parseBoolValue(!negateNext, errContext)
-			if err != nil {
-				return false, err
-			}
-
-			negateNext = false
-			continue
-		}
-
-		if negateNext {
-			return false, fmt.Errorf("parsing %s as %s: %w", errContext(), typeMetaM[def.Type].name, genericErr{
-				Err:     ErrIncompatibleValue,
-				Wrapped: errors.New("only Bool option definitions can use negating prefix '-'"),
-			})
-		}
-		// valueful opt, ending clustering loop
-
-		if len(argRune)-1 == optI {
-			consumedNext, value = lookAheadValue(args[1])
-		} else {
-			// value in same arg
-			value = string(argRune[optI+1:])
-			value = strings.TrimPrefix(value, "=")
-		}
+	return def.parseValueExpand(tok.Value, lookup, SourceArgs, errContext)
+}
 
-		return consumedNext, def.parseValue(value, errContext)
+// for errors
+func tokenContext(tok Token) string {
+	optKind := "short"
+	if tok.Kind == TokenLong {
+		optKind = "long"
 	}
 
-	return false, nil
+	return fmt.Sprintf("%s option %s (argument %d)", optKind, tok.Key, tok.Index)
 }
 
 func lookAheadValue(nextArg string) (consumedNext bool, value string) {
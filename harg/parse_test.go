@@ -185,6 +185,13 @@ func TestParseLongOptEat(t *testing.T) {
 
 	require.Equal(t, true, defs[kTwo].Default())
 	require.Equal(t, false, defs[kFoo].Default())
+
+	// values keep their case
+	defs = harg.Definitions{kOne: {Type: harg.String}}
+	_, _, err = defs.Parse([]string{"--OかE=MiXed"}, nil)
+	require.Nil(t, err)
+	s, _ := defs[kOne].String()
+	require.Equal(t, "MiXed", s)
 }
 
 func TestParseShortOptEat(t *testing.T) {
@@ -333,6 +340,15 @@ func TestParseLongOptAlsoBool(t *testing.T) {
 	s, ok := defs[kTwo].String()
 	require.Equal(t, true, ok)
 	require.Equal(t, "true", s)
+
+	// "=" is a value, even if empty
+	defs = harg.Definitions{kOne: {Type: harg.String, AlsoBool: true}}
+	_, _, err = defs.Parse([]string{"--foo="}, nil)
+	require.Nil(t, err)
+	require.Equal(t, false, defs[kOne].IsBool())
+	s, ok = defs[kOne].String()
+	require.Equal(t, true, ok)
+	require.Equal(t, "", s)
 }
 
 func TestParseError(t *testing.T) {
@@ -0,0 +1,318 @@
+package harg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"golang.org/x/exp/slog"
+)
+
+// Parse() and ParseEnv() with normalization and indexing done once.
+//
+// Fields must not be changed after Compile(), which is called by the first Parse()/ParseEnv().
+// Definitions added after Compile() are not seen.
+//
+//	p := harg.Parser{Definitions: defs, Chokes: []string{"serve"}}
+//	if err := p.Compile(); err != nil { ... } // optional, validates definitions
+//	args, chokeReturn, err := p.Parse(os.Args[1:])
+type Parser struct {
+	Definitions Definitions  // options, see Definitions.Parse()
+	Env         Definitions  // environment, see Definitions.ParseEnv()
+	Chokes      []string     // see Definitions.Parse()
+	Positionals []Positional // arguments, see Positional; nil: arguments are not checked
+
+	// Case handling of keys, short options are always case sensitive.
+	// Defaults are case insensitive (as Definitions.Parse(), ParseEnv(), ParseConfig()). Definitions keys are normalized
+	// (aliased) accordingly: long options are case folded (mostly lowercase), environment uppercase.
+	// Definitions keep LongCase for AliasGroups(), Render(), Export() and Merge().
+	LongCase, ChokeCase, EnvCase CaseMode
+
+	// harg additions to GNU to disable, eg ExtAll to mimic getopt_long.
+	Disable Extension
+
+	// Repeatable records of options, see Group and Result.Records.
+	Group *Group
+
+	// Called for each token, in order of args (eg for order-sensitive options), errors stop parsing.
+	// Options are parsed to def (nil if not defined) before Visit.
+	Visit func(tok Token, def *Definition) error
+	// Options are only passed to Visit, not parsed to Definitions (nor errored if not defined).
+	VisitOnly bool
+
+	// Read for Definition.File values "@-", default os.Stdin.
+	Stdin io.Reader
+
+	// Called when a deprecated name is parsed (option: "--old", "-o"; environment: "OLD").
+	// Defaults to a slog warning.
+	Warn func(name string, deprecation Deprecation)
+
+	compiled bool
+	lexer
+	opts map[string]*Definition // key: short or LongCase normalized long option
+	env  map[string]*Definition // key: EnvCase normalized
+}
+
+// Additions to GNU argument syntax, see FORMAT.md.
+type Extension uint8 // flags:
+const (
+	ExtLongNegation   Extension = 1 << iota // `---key`; disabled: long option "-key"
+	ExtShortNegation                        // `--k`, `-a-b`; disabled: long option "k", short option "-"
+	ExtLongSpaceValue                       // `--key value`; disabled: `--key` is missing a value (ErrIncompatibleValue), use `--key=value`
+
+	ExtAll = ExtLongNegation | ExtShortNegation | ExtLongSpaceValue
+)
+
+// Normalizes and indexes Definitions. Errors are of ErrInvalidDefinition.
+func (p *Parser) Compile() error {
+	if p.compiled {
+		return nil
+	}
+
+	if err := p.Definitions.normalizeOptsCase(p.LongCase); err != nil {
+		return err
+	}
+	if err := p.Env.normalizeEnvCase(p.EnvCase); err != nil {
+		return err
+	}
+	if err := p.compilePositionals(); err != nil {
+		return err
+	}
+	if err := p.compileGroup(); err != nil {
+		return err
+	}
+
+	p.opts = p.Definitions // normalized, has each key as optKey() (as optIndex())
+
+	if len(p.Env) != 0 {
+		p.env = make(map[string]*Definition, len(p.Env))
+	}
+	for key, def := range p.Env {
+		p.env[p.EnvCase.normalize(key)] = def
+	}
+
+	p.lexer = lexer{
+		chokes: chokeIndex(p.Chokes, p.ChokeCase),
+		lookup: func(key string) *Definition { return p.opts[key] },
+
+		longCase: p.LongCase, chokeCase: p.ChokeCase,
+		disable: p.Disable,
+	}
+	p.compiled = true
+	return nil
+}
+
+func (p *Parser) optKey(key string) string {
+	if isShortKey(key) {
+		return key
+	}
+
+	return p.LongCase.normalize(key)
+}
+
+// Deprecation of name of def (normalized with normalize, as key), looked up on use: deprecated definitions are rare.
+// name must be in defs: options and environment names of a Definition are deprecated separately.
+func (def *Definition) deprecation(key string, normalize func(string) string, defs Definitions) (Deprecation, bool) {
+	if def == nil {
+		return Deprecation{}, false
+	}
+
+	for name, deprecation := range def.Deprecated {
+		if defs[name] == def && normalize(name) == key {
+			return deprecation, true
+		}
+	}
+
+	return Deprecation{}, false
+}
+
+func (p *Parser) warn(name string, deprecation Deprecation) {
+	if p.Warn != nil {
+		p.Warn(name, deprecation)
+		return
+	}
+
+	slog.Warn("deprecated", slog.String("name", name),
+		slog.String("replacement", deprecation.Replacement), slog.String("message", deprecation.Message))
+}
+
+// parseOption() with deprecation warnings and Definition.File
+func (p *Parser) parseOption(tok Token, def *Definition) error {
+	if deprecation, ok := def.deprecation(tok.Key, p.optKey, p.Definitions); ok {
+		prefix := "--"
+		if tok.Kind == TokenShort {
+			prefix = "-"
+		}
+
+		p.warn(prefix+tok.Key, deprecation)
+	}
+
+	if def == nil {
+		return parseOption(tok, def, nil)
+	}
+
+	lookup := def.Expand
+	if def.File && tok.HasValue && !tok.Negated {
+		var read bool
+		var err error
+		if tok.Value, read, err = p.fileValue(tok.Value, lookup, func() string { return tokenContext(tok) }); err != nil {
+			return err
+		}
+
+		if read {
+			lookup = nil // contents are literal
+		}
+	}
+
+	return parseOption(tok, def, lookup)
+}
+
+// See Definitions.Parse().
+func (p *Parser) Parse(args []string) (parsed, chokeReturn []string, err error) {
+	res, err := p.ParseResult(args)
+	return res.Args, res.ChokeReturn, err
+}
+
+// Parse() result, see Definitions.Parse().
+type Result struct {
+	Args        []string // non-options, including Dashed
+	Dashed      []string // arguments after the divider ("--"), subset of Args
+	Divider     int      // index of the divider in args, -1 if none
+	ChokeReturn []string
+	Records     []Record // of Parser.Group, in order of args
+}
+
+// Parse(), additionally telling apart arguments after the divider ("--").
+func (p *Parser) ParseResult(args []string) (res Result, _ error) {
+	res.Divider = -1
+
+	if err := p.Compile(); err != nil {
+		return Result{Divider: -1}, err
+	}
+
+	lx := p.lexer
+	if p.Group != nil {
+		lx.lookup = p.groupLookup(&res)
+	}
+
+	var argIndex []int // of res.Args
+	err := lx.lex(args, func(tok Token, def *Definition) error {
+		switch tok.Kind {
+		case TokenArgument:
+			res.Args, argIndex = append(res.Args, tok.Value), append(argIndex, tok.Index)
+			if res.Divider != -1 {
+				res.Dashed = append(res.Dashed, tok.Value)
+			}
+			if p.Group != nil && p.Group.Head == "" {
+				p.newRecord(&res, tok)
+			}
+		case TokenDivider:
+			res.Divider = tok.Index
+		case TokenChoke:
+			res.ChokeReturn = args[tok.Index:]
+		case TokenShort, TokenLong:
+			if p.Group != nil {
+				var err error
+				if def, err = p.groupDefinition(&res, tok, def); err != nil {
+					return err
+				}
+			}
+
+			if !p.VisitOnly {
+				if err := p.parseOption(tok, def); err != nil {
+					return err
+				}
+			}
+		}
+
+		if p.Visit != nil {
+			return p.Visit(tok, def)
+		}
+		return nil
+	})
+	if err == nil {
+		err = p.parsePositionals(res.Args, argIndex)
+	}
+	if err != nil {
+		return Result{Divider: -1}, err
+	}
+
+	return res, nil
+}
+
+// See Definitions.ParseEnv().
+func (p *Parser) ParseEnv() error {
+	if err := p.Compile(); err != nil {
+		return err
+	}
+
+	for _, env := range os.Environ() {
+		key, rawVal := parseEnviron(env)
+		errContext := func() string { return fmt.Sprintf("environment %s", key) }
+
+		def, fileKey, ok := p.envDefinition(key)
+		if !ok {
+			continue // ignore unrecognized env
+		}
+
+		if deprecation, ok := def.deprecation(p.EnvCase.normalize(fileKey), p.EnvCase.normalize, p.Env); ok {
+			p.warn(fileKey, deprecation)
+		}
+
+		lookup := def.Expand
+		if fileKey != key {
+			if lookup != nil {
+				rawVal = expand(rawVal, lookup) // path
+			}
+
+			var err error
+			if rawVal, err = p.readFile(rawVal, errContext); err != nil {
+				return err
+			}
+			lookup = nil // contents are literal
+		}
+
+		def.replaceLayer(SourceEnv)
+
+		vals := []string{rawVal}
+		if def.EnvCSV {
+			vals = splitCSV(rawVal)
+		}
+
+		for _, val := range vals {
+			if def.AlsoBool {
+				boolVal, err := strconv.ParseBool(val)
+				if err == nil {
+					def.parseBoolValue(boolVal, SourceEnv, errContext)
+				}
+			}
+
+			if err := def.parseValueExpand(val, lookup, SourceEnv, errContext); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Definition of environment key, including KEY_FILE for Definition.File (fileKey: KEY).
+func (p *Parser) envDefinition(key string) (_ *Definition, fileKey string, ok bool) {
+	if def, ok := p.env[p.EnvCase.normalize(key)]; ok {
+		return def, key, true
+	}
+
+	cut := len(key) - len(envFileSuffix)
+	if cut < 1 || p.EnvCase.normalize(key[cut:]) != p.EnvCase.normalize(envFileSuffix) {
+		return nil, "", false
+	}
+
+	fileKey = key[:cut]
+	def, ok := p.env[p.EnvCase.normalize(fileKey)]
+	if !ok || !def.File {
+		return nil, "", false
+	}
+
+	return def, fileKey, true
+}
@@ -0,0 +1,345 @@
+package harg_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser(t *testing.T) {
+	p := harg.Parser{
+		Definitions: harg.Definitions{
+			"Name": {Type: harg.String},
+			"v":    {},
+		},
+		Env: harg.Definitions{
+			"harg_test_parser": {Type: harg.Int},
+		},
+		Chokes: []string{"choke"},
+	}
+	require.Nil(t, p.Compile())
+	require.Nil(t, os.Setenv("HARG_TEST_PARSER", "1"))
+
+	for i := 0; i < 2; i++ {
+		args, chokeReturn, err := p.Parse([]string{"--name", "foo", "-v", "arg", "choke", "-v"})
+		require.Nil(t, err)
+		require.Equal(t, []string{"arg"}, args)
+		require.Equal(t, []string{"choke", "-v"}, chokeReturn)
+	}
+
+	sl, ok := p.Definitions["name"].SlString()
+	require.Equal(t, true, ok)
+	require.Equal(t, []string{"foo", "foo"}, sl)
+
+	require.Nil(t, p.ParseEnv())
+	i, ok := p.Env["HARG_TEST_PARSER"].Int()
+	require.Equal(t, true, ok)
+	require.Equal(t, 1, i)
+
+	_, _, err := p.Parse([]string{"--nodef"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+}
+
+func TestParserCompileError(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{Definitions: harg.Definitions{"1": {}}}
+	require.ErrorIs(t, p.Compile(), harg.ErrInvalidDefinition)
+
+	p = harg.Parser{Env: harg.Definitions{"no spaces": {}}}
+	require.ErrorIs(t, p.ParseEnv(), harg.ErrInvalidDefinition)
+}
+
+func benchmarkDefs() harg.Definitions {
+	defs := make(harg.Definitions)
+	for i := 0; i < 50; i++ {
+		defs[fmt.Sprintf("Option-%d", i)] = &harg.Definition{Type: harg.String}
+	}
+	defs["v"] = &harg.Definition{}
+
+	return defs
+}
+
+var benchmarkArgs = []string{
+	"--option-1", "foo", "--option-2=bar", "-vvv", "argument", "--option-49", "baz", "serve", "--option-3",
+}
+
+func BenchmarkDefinitionsParse(b *testing.B) {
+	defs := benchmarkDefs()
+	chokes := []string{"serve", "run"}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := defs.Parse(benchmarkArgs, chokes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// First Parse() of Definitions, normalizing (aliasing) them.
+func BenchmarkDefinitionsParseFirst(b *testing.B) {
+	chokes := []string{"serve", "run"}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		defs := benchmarkDefs()
+		b.StartTimer()
+
+		if _, _, err := defs.Parse(benchmarkArgs, chokes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserParse(b *testing.B) {
+	p := harg.Parser{Definitions: benchmarkDefs(), Chokes: []string{"serve", "run"}}
+	if err := p.Compile(); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := p.Parse(benchmarkArgs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParserCase(t *testing.T) {
+	lower, upper := &harg.Definition{Type: harg.String}, &harg.Definition{Type: harg.String}
+	p := harg.Parser{
+		Definitions: harg.Definitions{"foo": lower, "Foo": upper},
+		Env:         harg.Definitions{"harg_Test_Case": {Type: harg.String}},
+		Chokes:      []string{"Run"},
+
+		LongCase: harg.CaseSensitive, ChokeCase: harg.CaseSensitive, EnvCase: harg.CaseSensitive,
+	}
+
+	args, chokeReturn, err := p.Parse([]string{"--Foo=upper", "--foo", "lower", "run", "Run", "--FOO"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"run"}, args)
+	require.Equal(t, []string{"Run", "--FOO"}, chokeReturn)
+
+	s, _ := upper.String()
+	require.Equal(t, "upper", s)
+	s, _ = lower.String()
+	require.Equal(t, "lower", s)
+
+	_, _, err = p.Parse([]string{"--FOO"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+
+	require.Nil(t, os.Setenv("HARG_TEST_CASE", "upper"))
+	require.Nil(t, os.Setenv("harg_Test_Case", "exact"))
+	require.Nil(t, p.ParseEnv())
+	s, _ = p.Env["harg_Test_Case"].String()
+	require.Equal(t, "exact", s)
+
+	// canonical names keep their case
+	require.Equal(t, []string{"--Foo=upper", "--foo=lower"}, p.Definitions.Render())
+	require.Nil(t, p.Definitions.Merge(harg.Definitions{"FOO": {}}, harg.MergeError))
+	require.ErrorIs(t, p.Definitions.Merge(harg.Definitions{"Foo": {}}, harg.MergeError), harg.ErrInvalidDefinition)
+
+	require.Nil(t, p.ParseConfig(strings.NewReader(`{"Foo": "upper config"}`), harg.JSON))
+	s, _ = upper.String()
+	require.Equal(t, "upper", s) // args take precedence
+	layer, _ := upper.Layer(harg.SourceConfig)
+	require.Equal(t, []string{"upper config"}, layer)
+	layer, _ = lower.Layer(harg.SourceConfig)
+	require.Nil(t, layer)
+}
+
+func TestParserCaseFold(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{
+		Definitions: harg.Definitions{"kelvin": {}, "string": {}},
+		Chokes:      []string{"ΣΟΦΟΣ"},
+	}
+
+	// Kelvin sign, long s; final sigma
+	args, chokeReturn, err := p.Parse([]string{"--Kelvin", "--ſtring", "σοφος"})
+	require.Nil(t, err)
+	require.Nil(t, args)
+	require.Equal(t, []string{"σοφος"}, chokeReturn)
+
+	for _, key := range []string{"kelvin", "string"} {
+		b, _ := p.Definitions[key].Bool()
+		require.Equal(t, true, b, key)
+	}
+
+	// not folded with simple folding
+	_, _, err = p.Parse([]string{"--STRİNG"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+}
+
+func strictDefs() harg.Definitions {
+	return harg.Definitions{
+		"bool": {},
+		"b":    {},
+		"c":    {},
+		"str":  {Type: harg.String},
+	}
+}
+
+func TestParserStrictLongNegation(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{Definitions: strictDefs(), Disable: harg.ExtLongNegation}
+
+	_, _, err := p.Parse([]string{"---bool"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+
+	// other extensions are unaffected
+	args, _, err := p.Parse([]string{"--b", "-c-c", "--str", "val"})
+	require.Nil(t, err)
+	require.Nil(t, args)
+
+	sl, _ := p.Definitions["b"].SlBool()
+	require.Equal(t, []bool{false}, sl)
+	sl, _ = p.Definitions["c"].SlBool()
+	require.Equal(t, []bool{true, false}, sl)
+}
+
+func TestParserStrictShortNegation(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{Definitions: strictDefs(), Disable: harg.ExtShortNegation}
+
+	for _, in := range [][]string{{"--b"}, {"-b-c"}} {
+		_, _, err := p.Parse(in)
+		require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition, in)
+	}
+
+	_, _, err := p.Parse([]string{"---bool", "-bc"})
+	require.Nil(t, err)
+
+	b, _ := p.Definitions["bool"].Bool()
+	require.Equal(t, false, b)
+	c, _ := p.Definitions["c"].Count()
+	require.Equal(t, 1, c)
+}
+
+func TestParserStrictLongSpaceValue(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{Definitions: strictDefs(), Disable: harg.ExtLongSpaceValue}
+
+	for _, in := range [][]string{{"--str", "val"}, {"--str"}} {
+		_, _, err := p.Parse(in)
+		require.ErrorIs(t, err, harg.ErrIncompatibleValue, in)
+		require.ErrorContains(t, err, "missing value", in)
+	}
+
+	p = harg.Parser{Definitions: strictDefs(), Disable: harg.ExtLongSpaceValue}
+	args, _, err := p.Parse([]string{"--str=", "--str=val2", "--bool", "val", "-b-b"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"val"}, args)
+
+	sl, _ := p.Definitions["str"].SlString()
+	require.Equal(t, []string{"", "val2"}, sl)
+	c, _ := p.Definitions["b"].SlBool()
+	require.Equal(t, []bool{true, false}, c)
+}
+
+func TestParserStrictGNU(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{Definitions: strictDefs(), Disable: harg.ExtAll}
+	p.Definitions["s"] = &harg.Definition{Type: harg.String}
+
+	args, _, err := p.Parse([]string{"--str=x", "val", "-s", "val2", "-bc", "--bool"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"val"}, args)
+
+	s, _ := p.Definitions["s"].String()
+	require.Equal(t, "val2", s)
+
+	for _, in := range []string{"---bool", "--b", "-b-c"} {
+		_, _, err := p.Parse([]string{in})
+		require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition, in)
+	}
+}
+
+func TestParserDeprecated(t *testing.T) {
+	kEnv := "HARG_TEST_OLD_NAME"
+	defs := harg.Definitions{
+		"new-name": {Type: harg.String},
+	}
+	require.Nil(t, defs.Alias("Old-Name", "new-name"))
+	require.Nil(t, defs.Alias("o", "new-name"))
+	require.Nil(t, defs.Deprecate("Old-Name", harg.Deprecation{Replacement: "--new-name"}))
+	require.Nil(t, defs.Deprecate("o", harg.Deprecation{Message: "short options are going away"}))
+	require.ErrorIs(t, defs.Deprecate("nodef", harg.Deprecation{}), harg.ErrOptionHasNoDefinition)
+
+	env := harg.Definitions{kEnv: {Type: harg.String}}
+	require.Nil(t, env.Deprecate(kEnv, harg.Deprecation{Replacement: "NEW_NAME"}))
+	require.Nil(t, os.Setenv(kEnv, "env"))
+
+	var warned []string
+	p := harg.Parser{
+		Definitions: defs, Env: env,
+		Warn: func(name string, deprecation harg.Deprecation) {
+			warned = append(warned, name+":"+deprecation.Replacement+deprecation.Message)
+		},
+	}
+
+	_, _, err := p.Parse([]string{"--new-name=a", "--old-name", "b", "-o", "c"})
+	require.Nil(t, err)
+	require.Nil(t, p.ParseEnv())
+
+	require.Equal(t, []string{
+		"--old-name:--new-name",
+		"-o:short options are going away",
+		kEnv + ":NEW_NAME",
+	}, warned)
+
+	sl, _ := defs["new-name"].SlString()
+	require.Equal(t, []string{"a", "b", "c"}, sl)
+
+	// options and environment are separate
+	const kToken = "HARG_TEST_TOKEN"
+	token := &harg.Definition{Type: harg.String, Deprecated: map[string]harg.Deprecation{kToken: {}}}
+	require.Nil(t, os.Setenv(kToken, "env"))
+	defer os.Unsetenv(kToken)
+
+	warned = nil
+	p = harg.Parser{
+		Definitions: harg.Definitions{strings.ToLower(kToken): token}, Env: harg.Definitions{kToken: token},
+		Warn: func(name string, _ harg.Deprecation) { warned = append(warned, name) },
+	}
+	_, _, err = p.Parse([]string{"--harg_test_token=a"})
+	require.Nil(t, err)
+	require.Nil(t, warned)
+	require.Nil(t, p.ParseEnv())
+	require.Equal(t, []string{kToken}, warned)
+}
+
+func TestParseResult(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{"v": {}}
+	res, err := defs.ParseResult([]string{"exec", "-v", "foo", "--", "ls", "-la", "--"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, harg.Result{
+		Args:    []string{"exec", "foo", "ls", "-la", "--"},
+		Dashed:  []string{"ls", "-la", "--"},
+		Divider: 3,
+	}, res)
+
+	res, err = defs.ParseResult([]string{"foo", "choke", "--", "bar"}, []string{"choke"})
+	require.Nil(t, err)
+	require.Equal(t, harg.Result{
+		Args:        []string{"foo"},
+		Divider:     -1,
+		ChokeReturn: []string{"choke", "--", "bar"},
+	}, res)
+
+	res, err = defs.ParseResult([]string{"--"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, harg.Result{Divider: 0}, res)
+}
@@ -0,0 +1,122 @@
+package harg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A non-option argument, see Parser.Positionals. Values are retrieved with Parser.Positional().
+//
+// Positionals are assigned arguments in order, a Variadic one takes the remaining arguments.
+type Positional struct {
+	Name     string            // for errors ("missing argument SOURCE") and Parser.Positional()
+	Type     Type              // as Definition.Type, Bool is parsed as a value ("true", "false")
+	Validate func(v any) error // see Definition.Validate
+
+	Required bool // Variadic: at least 1 (or Min) arguments
+	Variadic bool // only the last Positional
+	Min, Max int  // Variadic only; Max 0: unlimited
+
+	def *Definition
+}
+
+func (p *Parser) compilePositionals() error {
+	var optional bool
+	for i := range p.Positionals {
+		pos := &p.Positionals[i]
+		errContext := func() string { return fmt.Sprintf("positional %s", pos.Name) }
+
+		var err error
+		switch {
+		case pos.Name == "":
+			err = errors.New("Name must not be empty")
+		case pos.Type > TypeMax:
+			err = errors.New("Type does not exist")
+		case pos.Variadic && i != len(p.Positionals)-1:
+			err = errors.New("only the last Positional can be Variadic")
+		case pos.Required && optional:
+			err = errors.New("required Positional can't follow an optional one")
+		case pos.Min < 0 || pos.Max < 0 || (pos.Max != 0 && pos.Min > pos.Max):
+			err = errors.New("Min and Max must be positive, Min <= Max")
+		case !pos.Variadic && (pos.Min != 0 || pos.Max != 0):
+			err = errors.New("Min and Max are for Variadic")
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", errContext(), genericErr{Err: ErrInvalidDefinition, Wrapped: err})
+		}
+
+		optional = optional || !pos.Required
+		pos.def = &Definition{Type: pos.Type, Validate: pos.Validate}
+	}
+
+	return nil
+}
+
+// Range of arguments pos takes.
+func (pos *Positional) arity() (min, max int) {
+	if !pos.Variadic {
+		if pos.Required {
+			return 1, 1
+		}
+		return 0, 1
+	}
+
+	min, max = pos.Min, pos.Max
+	if pos.Required && min == 0 {
+		min = 1
+	}
+	if max == 0 {
+		max = -1
+	}
+
+	return min, max
+}
+
+// Parses args (index: their indexes in Parse() args) to Positionals.
+// Without Positionals, args are not checked.
+func (p *Parser) parsePositionals(args []string, index []int) error {
+	if len(p.Positionals) == 0 {
+		return nil
+	}
+
+	for i := range p.Positionals {
+		pos := &p.Positionals[i]
+
+		min, max := pos.arity()
+		if len(args) < min {
+			return fmt.Errorf("%w %s", ErrMissingArgument, pos.Name)
+		}
+
+		n := len(args)
+		if max != -1 && n > max {
+			n = max
+		}
+
+		for j, arg := range args[:n] {
+			errContext := func() string { return fmt.Sprintf("argument %s (argument %d)", pos.Name, index[j]) }
+
+			if err := pos.def.parseValue(arg, SourceArgs, errContext); err != nil {
+				return err
+			}
+		}
+
+		args, index = args[n:], index[n:]
+	}
+
+	if len(args) != 0 {
+		return fmt.Errorf("%w %q (argument %d)", ErrExtraArgument, args[0], index[0])
+	}
+
+	return nil
+}
+
+// Definition of a Positional by Name, with values from Parse(). nil if not found (or before Compile()).
+func (p *Parser) Positional(name string) *Definition {
+	for i := range p.Positionals {
+		if p.Positionals[i].Name == name {
+			return p.Positionals[i].def
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package harg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositional(t *testing.T) {
+	t.Parallel()
+
+	p := harg.Parser{
+		Definitions: harg.Definitions{"v": {}},
+		Positionals: []harg.Positional{
+			{Name: "SOURCE", Type: harg.String, Required: true},
+			{Name: "TIMEOUT", Type: harg.Duration},
+			{Name: "DEST", Type: harg.String, Variadic: true, Max: 2},
+		},
+	}
+	require.Nil(t, p.Compile())
+
+	args, _, err := p.Parse([]string{"src", "-v", "5s", "--", "a", "-b"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"src", "5s", "a", "-b"}, args)
+
+	s, _ := p.Positional("SOURCE").String()
+	require.Equal(t, "src", s)
+	d, _ := p.Positional("TIMEOUT").Duration()
+	require.Equal(t, 5*time.Second, d)
+	sl, _ := p.Positional("DEST").SlString()
+	require.Equal(t, []string{"a", "-b"}, sl)
+	require.Nil(t, p.Positional("NONE"))
+}
+
+func TestPositionalArity(t *testing.T) {
+	t.Parallel()
+
+	newParser := func() *harg.Parser {
+		return &harg.Parser{Positionals: []harg.Positional{
+			{Name: "SOURCE", Type: harg.String, Required: true},
+			{Name: "N", Type: harg.Int, Variadic: true, Min: 2},
+		}}
+	}
+
+	_, _, err := newParser().Parse([]string{"-"})
+	require.ErrorIs(t, err, harg.ErrMissingArgument)
+	require.ErrorContains(t, err, "missing argument N")
+
+	_, _, err = newParser().Parse([]string{"-", "1", "x"})
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+	require.ErrorContains(t, err, "argument N (argument 2)")
+
+	p := newParser()
+	_, _, err = p.Parse([]string{"-", "1", "2", "3"})
+	require.Nil(t, err)
+	sl, _ := p.Positional("N").SlInt()
+	require.Equal(t, []int{1, 2, 3}, sl)
+
+	for _, args := range [][]string{nil, {"--"}} {
+		_, _, err = (&harg.Parser{Positionals: []harg.Positional{{Name: "A", Required: true}}}).Parse(args)
+		require.ErrorIs(t, err, harg.ErrMissingArgument, args)
+		require.ErrorContains(t, err, "missing argument A", args)
+	}
+
+	_, _, err = (&harg.Parser{Positionals: []harg.Positional{{Name: "A", Type: harg.String}}}).Parse([]string{"a", "b"})
+	require.ErrorIs(t, err, harg.ErrExtraArgument)
+
+	for _, invalid := range [][]harg.Positional{
+		{{Name: ""}},
+		{{Name: "A", Variadic: true}, {Name: "B"}},
+		{{Name: "A"}, {Name: "B", Required: true}},
+		{{Name: "A", Variadic: true, Min: 3, Max: 2}},
+		{{Name: "A", Min: 1}},
+	} {
+		p := harg.Parser{Positionals: invalid}
+		require.ErrorIs(t, p.Compile(), harg.ErrInvalidDefinition)
+	}
+}
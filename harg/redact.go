@@ -0,0 +1,80 @@
+package harg
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/exp/slog"
+)
+
+const redacted = "REDACTED"
+
+// Formats the resolved value (as SlAny(), nil if not set), "REDACTED" if Sensitive. Implements fmt.Formatter.
+// Value receiver: both Definition and *Definition are redacted.
+func (def Definition) Format(f fmt.State, verb rune) {
+	var v any = redacted
+	if !def.Sensitive {
+		v = nil
+		if def.value() != nil {
+			v, _ = def.SlAny()
+		}
+	}
+
+	fmt.Fprintf(f, formatDirective(f, verb), v)
+}
+
+// Reconstructs the directive Format() was called with, eg "%-8v".
+func formatDirective(f fmt.State, verb rune) string {
+	directive := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive += string(flag)
+		}
+	}
+
+	if width, ok := f.Width(); ok {
+		directive += strconv.Itoa(width)
+	}
+	if precision, ok := f.Precision(); ok {
+		directive += "." + strconv.Itoa(precision)
+	}
+
+	return directive + string(verb)
+}
+
+// Resolved value (as SlAny()), "REDACTED" if Sensitive. Implements slog.LogValuer (for Definition and *Definition).
+func (def Definition) LogValue() slog.Value {
+	if def.Sensitive {
+		return slog.StringValue(redacted)
+	}
+	if def.value() == nil {
+		return slog.AnyValue(nil)
+	}
+
+	v, _ := def.SlAny()
+	return slog.AnyValue(v)
+}
+
+// Error of a Sensitive value, its message replaced with "REDACTED". Unwrap() returns the original.
+//
+// The message is not searched for the value, as it may be converted (`+7` as `7`) or a substring of other text.
+type redactedErr struct {
+	err error
+}
+
+func (e redactedErr) Error() string {
+	return redacted
+}
+
+func (e redactedErr) Unwrap() error {
+	return e.err
+}
+
+// Hides err's message, if def is Sensitive.
+func (def *Definition) redactErr(err error) error {
+	if !def.Sensitive {
+		return err
+	}
+
+	return redactedErr{err: err}
+}
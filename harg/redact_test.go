@@ -0,0 +1,74 @@
+package harg_test
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+)
+
+func TestSensitive(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"token": {Type: harg.String, Sensitive: true},
+		"name":  {Type: harg.String},
+		"unset": {Type: harg.Int},
+	}
+	_, _, err := defs.Parse([]string{"--token=hunter2", "--name=foo"}, nil)
+	require.Nil(t, err)
+
+	s, _ := defs["token"].String()
+	require.Equal(t, "hunter2", s) // retrievable
+
+	require.Equal(t, "REDACTED [foo] <nil>", fmt.Sprint(defs["token"], defs["name"], defs["unset"]))
+	require.Equal(t, `"REDACTED" [ "foo"]`, fmt.Sprintf("%q %6q", defs["token"], defs["name"]))
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf))
+	log.Info("parsed", slog.Any("token", defs["token"]), slog.Any("name", defs["name"]))
+	require.Contains(t, buf.String(), "token=REDACTED name=[foo]")
+	require.NotContains(t, buf.String(), "hunter2")
+
+	// values
+	token := *defs["token"]
+	require.Equal(t, "REDACTED REDACTED", fmt.Sprintf("%v %+v", token, token))
+	require.Equal(t, "[foo]", fmt.Sprint(*defs["name"]))
+	buf.Reset()
+	log.Info("parsed", slog.Any("token", token))
+	require.Contains(t, buf.String(), "token=REDACTED")
+
+	json, err := defs.ExportJSON(nil)
+	require.Nil(t, err)
+	require.Contains(t, string(json), `"REDACTED"`)
+	require.NotContains(t, string(json), "hunter2")
+}
+
+func TestSensitiveError(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{"pin": {Type: harg.Int, Sensitive: true}}
+	_, _, err := defs.Parse([]string{"--pin", "12a4"}, nil)
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+	require.NotContains(t, err.Error(), "12a4")
+	require.Contains(t, err.Error(), "REDACTED")
+
+	defs = harg.Definitions{"pin": {Type: harg.String, Sensitive: true, Validate: func(v any) error {
+		return fmt.Errorf("%s is too short", v)
+	}}}
+	_, _, err = defs.Parse([]string{"--pin=123"}, nil)
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+	require.NotContains(t, err.Error(), "123")
+
+	// converted values
+	defs = harg.Definitions{"pin": {Type: harg.Int, Sensitive: true, Enum: []any{1, 2}}}
+	_, _, err = defs.Parse([]string{"--pin=+7"}, nil)
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+	require.NotContains(t, err.Error(), "7")
+	require.Contains(t, err.Error(), "REDACTED")
+}
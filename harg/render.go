@@ -0,0 +1,66 @@
+package harg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Renders values set by the user (not Default()) back to arguments,
+// resulting in identical values when parsed with Parse().
+//
+// Options are in order of their canonical (longest long) name, values
+// are attached with "=" (`--key=value`, `-k=value`), bools are negated
+// with prefix "-" (`---key`, `--k`), and each value is repeated (`--key=a --key=b`).
+// Values of File definitions starting with "@" are escaped ("@@"),
+// as are "$" ("$$") and a leading "~" ("~~") of Expand definitions.
+func (defs Definitions) Render() (args []string) {
+	for _, g := range defs.AliasGroups() {
+		def := g.Definition
+		if def.Default() {
+			continue
+		}
+		name := g.Canonical()
+
+		prefix := "--"
+		if utf8.RuneCountInString(name) == 1 {
+			prefix = "-"
+		}
+
+		values := def.value().format()
+
+		if def.valueType() == Bool {
+			for _, v := range values {
+				negate := ""
+				if v == "false" {
+					negate = "-"
+				}
+
+				args = append(args, prefix+negate+name)
+			}
+
+			continue
+		}
+
+		for _, v := range values {
+			args = append(args, prefix+name+"="+def.renderValue(v))
+		}
+	}
+
+	return args
+}
+
+// Escapes v to parse back as is, see Definition.Expand and Definition.File (unescaped in reverse order).
+func (def *Definition) renderValue(v string) string {
+	if def.Expand != nil {
+		v = strings.ReplaceAll(v, "$", "$$")
+		if strings.HasPrefix(v, "~") {
+			v = "~" + v
+		}
+	}
+
+	if def.File && strings.HasPrefix(v, "@") {
+		v = "@" + v
+	}
+
+	return v
+}
@@ -0,0 +1,98 @@
+package harg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func renderDefs() harg.Definitions {
+	defs := harg.Definitions{
+		"name":     {Type: harg.String},
+		"verbose":  {},
+		"d":        {Type: harg.Duration},
+		"float":    {Type: harg.Float64},
+		"alsobool": {Type: harg.String, AlsoBool: true},
+		"unset":    {Type: harg.Int, DefaultValue: 1},
+	}
+	_ = defs.Alias("n", "name")
+	_ = defs.Alias("v", "verbose")
+
+	return defs
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	defs := renderDefs()
+	_, _, err := defs.Parse([]string{
+		"-n", "Hello World", "-n=-dash",
+		"-vv-v",
+		"-d", "90s",
+		"--float=0.1",
+		"--alsobool=",
+		"argument",
+	}, nil)
+	require.Nil(t, err)
+
+	args := defs.Render()
+	require.Equal(t, []string{
+		"--alsobool=",
+		"-d=1m30s",
+		"--float=0.1",
+		"--name=Hello World", "--name=-dash",
+		"--verbose", "--verbose", "---verbose",
+	}, args)
+
+	// round-trip
+	again := renderDefs()
+	_, _, err = again.Parse(args, nil)
+	require.Nil(t, err)
+
+	for _, key := range []string{"name", "verbose", "d", "float", "alsobool", "unset"} {
+		want, _ := defs[key].SlAny()
+		got, _ := again[key].SlAny()
+		require.Equal(t, want, got, key)
+		require.Equal(t, defs[key].Default(), again[key].Default(), key)
+	}
+
+	d, _ := again["d"].Duration()
+	require.Equal(t, 90*time.Second, d)
+
+	// File
+	defs = harg.Definitions{"pw": {Type: harg.String, File: true}}
+	_, _, err = defs.Parse([]string{"--pw=@@lit"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, []string{"--pw=@@lit"}, defs.Render())
+
+	again = harg.Definitions{"pw": {Type: harg.String, File: true}}
+	_, _, err = again.Parse(defs.Render(), nil)
+	require.Nil(t, err)
+	s, _ := again["pw"].String()
+	require.Equal(t, "@lit", s)
+
+	// Expand
+	lookup := func(key string) (string, bool) { return "/home/foo", key == "HOME" }
+	expandDefs := func() harg.Definitions {
+		return harg.Definitions{"path": {Type: harg.String, Slice: harg.SliceAppend, File: true, Expand: lookup}}
+	}
+
+	defs = expandDefs()
+	_, _, err = defs.Parse([]string{"--path=$$HOME", "--path=~~/x", "--path=@@$$a"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, []string{"--path=$$HOME", "--path=~~/x", "--path=@@$$a"}, defs.Render())
+
+	again = expandDefs()
+	_, _, err = again.Parse(defs.Render(), nil)
+	require.Nil(t, err)
+	sl, _ := again["path"].SlString()
+	require.Equal(t, []string{"$HOME", "~/x", "@$a"}, sl)
+
+	// uppercase long option
+	defs = harg.Definitions{"DEBUG": {}}
+	_, _, err = defs.Parse([]string{"--DEBUG"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, []string{"--debug"}, defs.Render())
+}
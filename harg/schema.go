@@ -0,0 +1,120 @@
+package harg
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// JSON Schema (draft 2020-12) subset, see Definitions.Schema().
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	AnyOf      []*Schema          `json:"anyOf,omitempty"`
+
+	Enum      []any    `json:"enum,omitempty"`
+	Default   any      `json:"default,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	WriteOnly bool     `json:"writeOnly,omitempty"`
+}
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// time.ParseDuration()
+const durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+var schemaTypes = map[Type]func() *Schema{
+	Bool:     func() *Schema { return &Schema{Type: "boolean"} },
+	String:   func() *Schema { return &Schema{Type: "string"} },
+	Int:      func() *Schema { return &Schema{Type: "integer"} },
+	Int64:    func() *Schema { return &Schema{Type: "integer"} },
+	Uint:     func() *Schema { return &Schema{Type: "integer", Minimum: new(float64)} },
+	Uint64:   func() *Schema { return &Schema{Type: "integer", Minimum: new(float64)} },
+	Float64:  func() *Schema { return &Schema{Type: "number"} },
+	Duration: func() *Schema { return &Schema{Type: "string", Pattern: durationPattern} },
+}
+
+// Object schema of Definitions, a property per canonical name (see AliasGroups()), eg for configuration documents.
+//
+// Values are single, unless Slice is set (not SliceAppend) or DefaultValue is a slice (array).
+// Configuration documents may use a list for any key (repeated values) or a single value for an array.
+// AlsoBool is either a boolean or Type. Sensitive values are writeOnly, without a default.
+func (defs Definitions) Schema() *Schema {
+	s := &Schema{Schema: schemaDialect, Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, g := range defs.AliasGroups() {
+		key := g.Canonical()
+		s.Properties[key] = g.Definition.schema()
+
+		if g.Definition.Required {
+			s.Required = append(s.Required, key)
+		}
+	}
+
+	return s
+}
+
+func (def *Definition) schema() *Schema {
+	value := schemaTypes[def.Type]()
+	for _, v := range def.Enum {
+		value.Enum = append(value.Enum, schemaValue(v))
+	}
+
+	s := value
+	if def.AlsoBool {
+		s = &Schema{AnyOf: []*Schema{schemaTypes[Bool](), value}}
+	}
+
+	array := def.Slice != SliceAppend || isSlice(def.DefaultValue)
+	if array {
+		s = &Schema{Type: "array", Items: s}
+	}
+
+	s.Description = def.Usage
+	s.WriteOnly = def.Sensitive
+	if !def.Sensitive {
+		s.Default = def.schemaDefault(array)
+	}
+
+	return s
+}
+
+// DefaultValue, as a slice if array, Duration as string (as accepted by time.ParseDuration()).
+func (def *Definition) schemaDefault(array bool) any {
+	v := reflect.ValueOf(def.DefaultValue)
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Kind() != reflect.Slice && array:
+		return []any{schemaValue(def.DefaultValue)}
+	case v.Kind() != reflect.Slice:
+		return schemaValue(def.DefaultValue)
+	case v.Len() == 0:
+		return nil
+	}
+
+	defaults := make([]any, v.Len())
+	for i := range defaults {
+		defaults[i] = schemaValue(v.Index(i).Interface())
+	}
+	return defaults
+}
+
+func schemaValue(v any) any {
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+
+	return v
+}
+
+// Schema() as indented JSON.
+func (defs Definitions) SchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(defs.Schema(), "", "  ")
+}
@@ -0,0 +1,91 @@
+package harg_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"level":   {Type: harg.String, Usage: "log level", Enum: []any{"debug", "info"}, DefaultValue: "info"},
+		"port":    {Type: harg.Uint, Required: true},
+		"timeout": {Type: harg.Duration, DefaultValue: 5 * time.Second},
+		"tag":     {Type: harg.String, Slice: harg.SliceSplit},
+		"env":     {Type: harg.String, Slice: harg.SliceReplace, Enum: []any{"a", "b"}, DefaultValue: "a"},
+		"dns":     {Type: harg.String, DefaultValue: []string{"1.1.1.1", "8.8.8.8"}},
+		"user":    {Type: harg.String, DefaultValue: []string{"root"}},
+		"group":   {Type: harg.String, DefaultValue: []string(nil)},
+		"color":   {Type: harg.String, AlsoBool: true},
+		"TOKEN":   {Type: harg.String, Sensitive: true, DefaultValue: "secret"}, // long option, case folded
+		"v":       {},
+	}
+	require.Nil(t, defs.Alias("verbose", "v"))
+
+	b, err := defs.SchemaJSON()
+	require.Nil(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"level": {"type": "string", "description": "log level", "enum": ["debug", "info"], "default": "info"},
+			"port": {"type": "integer", "minimum": 0},
+			"timeout": {"type": "string", "pattern": "^[-+]?(0|(([0-9]+(\\.[0-9]*)?|\\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$", "default": "5s"},
+			"tag": {"type": "array", "items": {"type": "string"}},
+			"env": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}, "default": ["a"]},
+			"dns": {"type": "array", "items": {"type": "string"}, "default": ["1.1.1.1", "8.8.8.8"]},
+			"user": {"type": "array", "items": {"type": "string"}, "default": ["root"]},
+			"group": {"type": "array", "items": {"type": "string"}},
+			"color": {"anyOf": [{"type": "boolean"}, {"type": "string"}]},
+			"token": {"type": "string", "writeOnly": true},
+			"verbose": {"type": "boolean"}
+		},
+		"required": ["port"]
+	}`, string(b))
+}
+
+func TestSchemaDuration(t *testing.T) {
+	t.Parallel()
+
+	pattern := regexp.MustCompile(harg.Definitions{"d": {Type: harg.Duration}}.Schema().Properties["d"].Pattern)
+	for _, s := range []string{"0", "1s", "-1.5h", ".5s", "1.s", "1h2m3s", "+10µs", "s", ".s", "", "1", "1x"} {
+		_, err := time.ParseDuration(s)
+		require.Equal(t, err == nil, pattern.MatchString(s), s)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{"color": {Type: harg.String, AlsoBool: true, Enum: []any{"auto", "never"}}}
+	_, _, err := defs.Parse([]string{"--color", "--color=auto"}, nil)
+	require.Nil(t, err)
+
+	_, _, err = defs.Parse([]string{"--color=always"}, nil)
+	require.ErrorIs(t, err, harg.ErrIncompatibleValue)
+
+	defs = harg.Definitions{"n": {Type: harg.Int, Enum: []any{"1"}}}
+	_, _, err = defs.Parse(nil, nil)
+	require.ErrorIs(t, err, harg.ErrInvalidDefinition)
+}
+
+func TestCheckRequired(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"port": {Type: harg.Int, Required: true},
+		"host": {Type: harg.String, Required: true, DefaultValue: "localhost"},
+	}
+	err := defs.CheckRequired()
+	require.ErrorIs(t, err, harg.ErrMissingArgument)
+	require.ErrorContains(t, err, "missing argument --port")
+
+	_, _, err = defs.Parse([]string{"--port=80"}, nil)
+	require.Nil(t, err)
+	require.Nil(t, defs.CheckRequired())
+}
@@ -0,0 +1,107 @@
+package harg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnterminatedQuote = errors.New("unterminated quote") // including trailing backslash
+
+// Splits s to arguments with POSIX shell quoting rules, eg for Parse().
+//
+// Words are separated by unquoted whitespace. Single quotes preserve everything,
+// double quotes allow escaping $, `, ", \ and newline, unquoted backslash escapes any character.
+// Expansions (variables, globs, ~) and comments are not supported, `$HOME` stays as is.
+func SplitWords(s string) (words []string, _ error) {
+	var (
+		word    strings.Builder
+		inWord  bool // "" is a word
+		escaped bool
+		quote   rune // 0, '\'', '"'
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+
+			switch {
+			case r == '\n': // line continuation
+				continue
+			case quote == '"' && !strings.ContainsRune("$`\"\\", r):
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			inWord = true
+
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+				continue
+			}
+			word.WriteRune(r)
+
+		case r == '\\':
+			escaped = true // word only if not a line continuation
+
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+				continue
+			}
+			word.WriteRune(r)
+
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, fmt.Errorf("trailing backslash: %w", ErrUnterminatedQuote)
+	case quote != 0:
+		return nil, fmt.Errorf("%c: %w", quote, ErrUnterminatedQuote)
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Joins args to a string, quoting as needed for SplitWords() or a POSIX shell.
+func JoinWords(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteWord(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func quoteWord(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	if strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("_-+=.,/:@%", r))
+	}) == -1 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,46 @@
+package harg_test
+
+import (
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitWords(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string][]string{
+		``:                           nil,
+		"  \t\n":                     nil,
+		`hello  world`:               {"hello", "world"},
+		`--foo='bar baz' -o"x y"z`:   {"--foo=bar baz", `-ox yz`},
+		`'' ""`:                      {"", ""},
+		`'it'\''s' "a \"b\" \$c \d"`: {"it's", `a "b" $c \d`},
+		`a\ b \'c \` + "\n" + `d`:    {"a b", "'c", "d"},
+		`$HOME ~/x`:                  {"$HOME", "~/x"},
+		`"line` + "\n" + `break"`:    {"line\nbreak"},
+		"a \\\n b":                   {"a", "b"},
+		"a\\\nb":                     {"ab"},
+	} {
+		got, err := harg.SplitWords(in)
+		require.Nil(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{`'unterminated`, `"unterminated`, `trailing\`, `"mixed'`} {
+		_, err := harg.SplitWords(in)
+		require.ErrorIs(t, err, harg.ErrUnterminatedQuote, in)
+	}
+}
+
+func TestJoinWords(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"plain", "--foo=bar", "", "with space", "it's", `"$HOME"`, "tab\there", "ünï"}
+	require.Equal(t, `plain --foo=bar '' 'with space' 'it'\''s' '"$HOME"' 'tab	here' 'ünï'`, harg.JoinWords(args))
+
+	got, err := harg.SplitWords(harg.JoinWords(args))
+	require.Nil(t, err)
+	require.Equal(t, args, got)
+}
@@ -0,0 +1,168 @@
+package harg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Where a value came from. Higher sources replace lower ones, see Definition.MergeSources.
+type Source uint8 // enum:
+const (
+	SourceDefault Source = iota // Definition default
+	SourceConfig                // defs.ParseConfig()
+	SourceEnv                   // defs.ParseEnv()
+	SourceArgs                  // defs.Parse()
+) //
+const SourceMax = SourceArgs
+
+var sourceNames = map[Source]string{
+	SourceDefault: "default",
+	SourceConfig:  "config",
+	SourceEnv:     "env",
+	SourceArgs:    "args",
+}
+
+func (s Source) String() string {
+	return sourceNames[s]
+}
+
+// Sets SourceDefault from DefaultValue, if not done already (on normalization).
+func (def *Definition) initDefault() error {
+	if def.layers[SourceDefault] != nil {
+		return nil
+	}
+
+	o, err := def.defaultLayer()
+	if o != nil {
+		def.layers[SourceDefault] = o
+		def.resolve()
+	}
+	return err
+}
+
+// DefaultValue as an option, nil if none (or empty).
+func (def *Definition) defaultLayer() (option, error) {
+	if def.DefaultValue == nil || def.Type > TypeMax {
+		return nil, nil // Type is checked on normalization
+	}
+
+	types := []Type{def.Type}
+	if def.AlsoBool {
+		types = append(types, Bool)
+	}
+
+	for _, t := range types {
+		o := typeMetaM[t].new()
+
+		empty, ok := o.setDefault(def.DefaultValue)
+		if !ok {
+			continue
+		}
+
+		if empty {
+			return nil, nil
+		}
+		return o, nil
+	}
+
+	return nil, fmt.Errorf("DefaultValue of type %T does not match Type %s", def.DefaultValue, def.Type)
+}
+
+// v is of Type's Go type (T).
+func (def *Definition) isValueType(v any) bool {
+	_, ok := typeMetaM[def.Type].new().setDefault(v)
+	return ok && !isSlice(v)
+}
+
+func isSlice(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Slice
+}
+
+// Layers, including DefaultValue. Before normalization (see initDefault()), DefaultValue is read as is, def is not modified.
+func (def *Definition) currentLayers() [SourceMax + 1]option {
+	layers := def.layers
+	if layers[SourceDefault] == nil {
+		layers[SourceDefault], _ = def.defaultLayer() // reported on normalization
+	}
+
+	return layers
+}
+
+// Resolved value, including DefaultValue. Nil if not set.
+func (def *Definition) value() option {
+	if def.layers[SourceDefault] != nil || def.DefaultValue == nil {
+		return def.parsed
+	}
+
+	layers := def.currentLayers()
+	return def.resolveLayers(layers[:])
+}
+
+// Recomputes the value seen by getters from layers.
+func (def *Definition) resolve() {
+	def.parsed = def.resolveLayers(def.layers[:])
+}
+
+func (def *Definition) resolveLayers(layers []option) (parsed option) {
+	for _, layer := range layers {
+		if layer == nil {
+			continue
+		}
+
+		// replace lower layers; AlsoBool can't merge bools with values
+		if !def.MergeSources || parsed == nil || def.optionType(parsed) != def.optionType(layer) {
+			parsed = layer
+			continue
+		}
+
+		merged := typeMetaM[def.optionType(layer)].new()
+		merged.merge(parsed)
+		merged.merge(layer)
+		parsed = merged
+	}
+
+	return parsed
+}
+
+// Type of an option held by def, AlsoBool may hold Bool.
+func (def *Definition) optionType(o option) Type {
+	if _, isBool := o.(*optBool); isBool {
+		return Bool
+	}
+
+	return def.Type
+}
+
+// The highest source def has a value from (the one getters return).
+// With MergeSources, values of lower sources come first.
+func (def *Definition) Source() (src Source, ok bool) {
+	if def == nil {
+		return 0, false
+	}
+	layers := def.currentLayers()
+
+	for src := SourceMax; ; src-- {
+		if layers[src] != nil {
+			return src, true
+		}
+
+		if src == 0 {
+			return 0, false
+		}
+	}
+}
+
+// Values from a single source, as with SlAny().
+func (def *Definition) Layer(src Source) (v any, ok bool) {
+	if def == nil || src > SourceMax {
+		return nil, false
+	}
+	layers := def.currentLayers()
+
+	if layers[src] == nil {
+		return nil, false
+	}
+
+	return layers[src].contents(), true
+}
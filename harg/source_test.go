@@ -0,0 +1,138 @@
+package harg_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourcePrecedence(t *testing.T) {
+	kOne, kEnv := "layered", "HARG_TEST_LAYERED"
+	defs := harg.Definitions{
+		kOne: {Type: harg.String, EnvCSV: true},
+	}
+	require.Nil(t, defs.Alias(kEnv, kOne))
+	require.Nil(t, os.Setenv(kEnv, "env1,env2"))
+
+	// order of parsing does not matter
+	_, _, err := defs.Parse([]string{"--layered", "arg"}, nil)
+	require.Nil(t, err)
+	require.Nil(t, defs.ParseEnv())
+	require.Nil(t, defs.ParseConfig(strings.NewReader(`{"layered": "config"}`), harg.JSON))
+
+	sl, ok := defs[kOne].SlString()
+	require.Equal(t, true, ok)
+	require.Equal(t, []string{"arg"}, sl)
+
+	src, ok := defs[kOne].Source()
+	require.Equal(t, true, ok)
+	require.Equal(t, harg.SourceArgs, src)
+
+	env, ok := defs[kOne].Layer(harg.SourceEnv)
+	require.Equal(t, true, ok)
+	require.Equal(t, []string{"env1", "env2"}, env)
+
+	_, ok = defs[kOne].Layer(harg.SourceDefault)
+	require.Equal(t, false, ok)
+}
+
+func TestSourceMerge(t *testing.T) {
+	t.Parallel()
+
+	kOne := "merged"
+	defs := harg.Definitions{
+		kOne: {Type: harg.String, MergeSources: true},
+	}
+
+	_, _, err := defs.Parse([]string{"--merged", "arg"}, nil)
+	require.Nil(t, err)
+	require.Nil(t, defs.ParseConfig(strings.NewReader(`{"merged": ["c1", "c2"]}`), harg.JSON))
+
+	sl, ok := defs[kOne].SlString()
+	require.Equal(t, true, ok)
+	require.Equal(t, []string{"c1", "c2", "arg"}, sl)
+
+	src, _ := defs[kOne].Source()
+	require.Equal(t, harg.SourceArgs, src)
+}
+
+func TestSourceAlsoBool(t *testing.T) {
+	t.Parallel()
+
+	kOne := "alsobool"
+	defs := harg.Definitions{
+		kOne: {Type: harg.String, AlsoBool: true, MergeSources: true},
+	}
+
+	require.Nil(t, defs.ParseConfig(strings.NewReader(`{"alsobool": "config"}`), harg.JSON))
+	_, _, err := defs.Parse([]string{"--alsobool"}, nil)
+	require.Nil(t, err)
+
+	// bool and value can't be merged, higher source wins
+	require.Equal(t, true, defs[kOne].IsBool())
+	b, ok := defs[kOne].Bool()
+	require.Equal(t, true, ok)
+	require.Equal(t, true, b)
+
+	s, ok := defs[kOne].Layer(harg.SourceConfig)
+	require.Equal(t, true, ok)
+	require.Equal(t, []string{"config"}, s)
+}
+
+func TestSourceDefault(t *testing.T) {
+	t.Parallel()
+
+	kOne, kTwo, kThree := "one", "two", "three"
+	defs := harg.Definitions{
+		kOne:   {Type: harg.Duration, DefaultValue: 5 * time.Second},
+		kTwo:   {Type: harg.String, DefaultValue: []string{"a", "b"}},
+		kThree: {Type: harg.String, AlsoBool: true, DefaultValue: true},
+	}
+
+	// without parsing
+	d, ok := defs[kOne].Duration()
+	require.Equal(t, true, ok)
+	require.Equal(t, 5*time.Second, d)
+	require.Equal(t, true, defs[kOne].Default())
+
+	src, ok := defs[kOne].Source()
+	require.Equal(t, true, ok)
+	require.Equal(t, harg.SourceDefault, src)
+
+	require.Equal(t, true, defs[kThree].IsBool())
+	v, ok := defs[kThree].Any()
+	require.Equal(t, true, ok)
+	require.Equal(t, true, v)
+
+	// read as is until parsed
+	defs[kOne].DefaultValue = time.Second
+	d, _ = defs[kOne].Duration()
+	require.Equal(t, time.Second, d)
+	require.Equal(t, []string{"1s"}, defs.Export(nil)[0].Value)
+
+	_, _, err := defs.Parse([]string{"--two", "c"}, nil)
+	require.Nil(t, err)
+
+	sl, ok := defs[kTwo].SlString()
+	require.Equal(t, true, ok)
+	require.Equal(t, []string{"c"}, sl)
+	require.Equal(t, false, defs[kTwo].Default())
+
+	require.Equal(t, true, defs[kThree].IsBool())
+	require.Equal(t, true, defs[kThree].Default())
+}
+
+func TestSourceDefaultMismatch(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"int": {Type: harg.Int, DefaultValue: "one"},
+	}
+
+	_, _, err := defs.Parse([]string{"--int=1"}, nil)
+	require.ErrorIs(t, err, harg.ErrInvalidDefinition)
+}
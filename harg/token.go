@@ -0,0 +1,229 @@
+package harg
+
+import (
+	"strings"
+)
+
+type TokenKind uint8 // enum:
+const (
+	TokenArgument TokenKind = iota // non-option, Value: the argument
+	TokenDivider                   // "--", all following are TokenArgument
+	TokenShort                     // short option, one per clustered option (-abc: a, b, c)
+	TokenLong                      // long option
+	TokenChoke                     // Value: the choke, following arguments are not tokenized (chokeReturn)
+) //
+var tokenKindNames = map[TokenKind]string{
+	TokenArgument: "argument",
+	TokenDivider:  "divider",
+	TokenShort:    "short option",
+	TokenLong:     "long option",
+	TokenChoke:    "choke",
+}
+
+func (k TokenKind) String() string {
+	return tokenKindNames[k]
+}
+
+// A classified argument. See Tokenize().
+type Token struct {
+	Kind  TokenKind
+	Index int    // in args
+	Raw   string // args[Index]
+
+	Key       string // option key, long options case folded (see CaseMode)
+	Value     string // option value, argument or choke
+	HasValue  bool   // option has a value (may be empty)
+	ValueNext bool   // Value is args[Index+1]
+	Negated   bool   // prefixed with negating "-" (`---key`, `--k`, `-a-b`)
+	Cluster   int    // position of a short option in its argument (-abc: a:0, b:1, c:2)
+}
+
+// Classifies args (see Parse()) without parsing values to, or modifying Definitions.
+//
+// Definitions are used to tell apart bools from options taking a value (`-ovalue`, `--key value`),
+// options without a definition are tokenized as bools.
+// No errors are returned, invalid options (eg negated non-bools) are left for the consumer.
+func (defs Definitions) Tokenize(args []string, chokes []string) (tokens []Token) {
+	lx := lexer{chokes: chokeIndex(chokes, CaseInsensitive), lookup: defs.lookup}
+
+	_ = lx.lex(args, func(tok Token, _ *Definition) error {
+		tokens = append(tokens, tok)
+		return nil
+	})
+
+	return tokens
+}
+
+type lexer struct {
+	chokes map[string]struct{}          // see chokeIndex()
+	lookup func(key string) *Definition // key normalized by longCase
+
+	longCase, chokeCase CaseMode
+	disable             Extension
+}
+
+// Calls fn for each token, until a choke or an error from fn.
+// def is the option's Definition, nil if not found (or not an option).
+func (lx *lexer) lex(args []string, fn func(tok Token, def *Definition) error) error {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		kind := argumentKind(arg)
+		if kind == shortOption && lx.disable&ExtShortNegation != 0 && strings.HasPrefix(arg, "--") {
+			kind = longOption // "--k"
+		}
+
+		switch kind {
+		case argument:
+			if _, isChoke := lx.chokes[lx.chokeCase.normalize(arg)]; isChoke {
+				return fn(Token{Kind: TokenChoke, Index: i, Raw: arg, Value: arg}, nil)
+			}
+
+			if err := fn(Token{Kind: TokenArgument, Index: i, Raw: arg, Value: arg}, nil); err != nil {
+				return err
+			}
+
+		case argumentDivider:
+			if err := fn(Token{Kind: TokenDivider, Index: i, Raw: arg}, nil); err != nil {
+				return err
+			}
+
+			for j := i + 1; j < len(args); j++ {
+				if err := fn(Token{Kind: TokenArgument, Index: j, Raw: args[j], Value: args[j]}, nil); err != nil {
+					return err
+				}
+			}
+
+			return nil
+
+		case shortOption:
+			consumedNext, err := lx.lexShort(args[i:], i, fn)
+			if err != nil {
+				return err
+			}
+			if consumedNext {
+				i++
+			}
+
+		case longOption:
+			tok, def := lx.lexLong(args[i:], i)
+			if err := fn(tok, def); err != nil {
+				return err
+			}
+			if tok.ValueNext {
+				i++
+			}
+		}
+	}
+
+	return nil
+}
+
+// long option Bool (--foo) (---foo) or (--foo=value) (--foo) (--foo value)
+//
+// caller should ensure len(args[0]) > 2
+func (lx *lexer) lexLong(args []string, index int) (Token, *Definition) {
+	argName := args[0][2:] // [2:]: remove prefix "--"
+	if argName == "" {
+		panic("lexLong caller did not ensure len(args[0]) > 2")
+	}
+
+	tok := Token{Kind: TokenLong, Index: index, Raw: args[0]}
+
+	key, value, valueFound := strings.Cut(argName, "=")
+	key = lx.longCase.normalize(key) // values keep their case
+
+	tok.Key = key
+	if lx.disable&ExtLongNegation == 0 {
+		tok.Key, tok.Negated = trimPrefix(key, "-") // ---foo (three dashes negate)
+	}
+	tok.Value, tok.HasValue = value, valueFound
+
+	var def *Definition
+	if !isShortKey(tok.Key) || lx.disable&ExtShortNegation == 0 { // "--k" is not short option k
+		def = lx.lookup(tok.Key)
+	}
+
+	// Bool has no lookahead
+	if valueFound || tok.Negated || def == nil || def.Type == Bool || def.AlsoBool {
+		return tok, def
+	}
+
+	if lx.disable&ExtLongSpaceValue != 0 {
+		return tok, def // no value, `--key=value` only
+	}
+
+	tok.HasValue = true
+	if len(args) > 1 {
+		tok.ValueNext, tok.Value = lookAheadValue(args[1])
+	}
+
+	return tok, def
+}
+
+// short option(s) (-f) (-fff) (-fb) (-fbvalue) (-fb value) (--n) (-y-ny)
+//
+// caller should ensure len(args[0]) >= 2
+func (lx *lexer) lexShort(args []string, index int, fn func(Token, *Definition) error) (consumedNext bool, _ error) {
+	argRune := []rune(args[0][1:]) // [1:]: remove prefix "-"
+	if len(argRune) == 0 {
+		panic("lexShort caller did not ensure len(args[0]) > 1")
+	}
+
+	// loop through clustered (-abc = -a -b -c) options
+	var negateNext bool
+	var cluster int
+	for optI, opt := range argRune {
+		if opt == '-' && lx.disable&ExtShortNegation == 0 {
+			// short option prefix "-" negates
+			negateNext = true
+			continue
+		}
+
+		tok := Token{
+			Kind: TokenShort, Index: index, Raw: args[0],
+			Key: string(opt), Negated: negateNext, Cluster: cluster,
+		}
+		negateNext = false
+		cluster++
+
+		def := lx.lookup(tok.Key)
+		if def == nil || def.Type == Bool || def.AlsoBool {
+			if err := fn(tok, def); err != nil {
+				return false, err
+			}
+
+			continue
+		}
+		// valueful opt, ending clustering loop
+
+		tok.HasValue = true
+		if len(argRune)-1 == optI {
+			if len(args) > 1 {
+				tok.ValueNext, tok.Value = lookAheadValue(args[1])
+			}
+		} else {
+			// value in same arg
+			tok.Value = strings.TrimPrefix(string(argRune[optI+1:]), "=")
+		}
+
+		return tok.ValueNext, fn(tok, def)
+	}
+
+	return false, nil
+}
+
+// Definition for a normalized key, without requiring defs to be normalized.
+func (defs Definitions) lookup(key string) *Definition {
+	if def, ok := defs[key]; ok || isShortKey(key) {
+		return def
+	}
+
+	for k, def := range defs {
+		if strings.EqualFold(k, key) && k != "" && !isShortKey(k) {
+			return def
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package harg_test
+
+import (
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{
+		"Name": {Type: harg.String},
+		"o":    {Type: harg.String},
+		"v":    {},
+		"b":    {},
+	}
+
+	tokens := defs.Tokenize([]string{
+		"hello",
+		"--NAME", "World",
+		"-vb-vofoo",
+		"-o", "-v",
+		"---v",
+		"--undefined", "arg",
+		"choke", "--name=x",
+	}, []string{"choke"})
+
+	require.Equal(t, []harg.Token{
+		{Kind: harg.TokenArgument, Index: 0, Raw: "hello", Value: "hello"},
+		{Kind: harg.TokenLong, Index: 1, Raw: "--NAME", Key: "name", Value: "World", HasValue: true, ValueNext: true},
+		{Kind: harg.TokenShort, Index: 3, Raw: "-vb-vofoo", Key: "v"},
+		{Kind: harg.TokenShort, Index: 3, Raw: "-vb-vofoo", Key: "b", Cluster: 1},
+		{Kind: harg.TokenShort, Index: 3, Raw: "-vb-vofoo", Key: "v", Negated: true, Cluster: 2},
+		{Kind: harg.TokenShort, Index: 3, Raw: "-vb-vofoo", Key: "o", Value: "foo", HasValue: true, Cluster: 3},
+		{Kind: harg.TokenShort, Index: 4, Raw: "-o", Key: "o", HasValue: true},
+		{Kind: harg.TokenShort, Index: 5, Raw: "-v", Key: "v"},
+		{Kind: harg.TokenLong, Index: 6, Raw: "---v", Key: "v", Negated: true},
+		{Kind: harg.TokenLong, Index: 7, Raw: "--undefined", Key: "undefined"},
+		{Kind: harg.TokenArgument, Index: 8, Raw: "arg", Value: "arg"},
+		{Kind: harg.TokenChoke, Index: 9, Raw: "choke", Value: "choke"},
+	}, tokens)
+
+	// not normalized
+	_, ok := defs["name"]
+	require.Equal(t, false, ok)
+
+	tokens = defs.Tokenize([]string{"-o", "--", "-v", "choke"}, []string{"choke"})
+	require.Equal(t, []harg.Token{
+		{Kind: harg.TokenShort, Index: 0, Raw: "-o", Key: "o", HasValue: true},
+		{Kind: harg.TokenDivider, Index: 1, Raw: "--"},
+		{Kind: harg.TokenArgument, Index: 2, Raw: "-v", Value: "-v"},
+		{Kind: harg.TokenArgument, Index: 3, Raw: "choke", Value: "choke"},
+	}, tokens)
+}
@@ -0,0 +1,105 @@
+package harg
+
+// Definitions with subcommands, parsed in one pass with Tree.Parse().
+type Tree struct {
+	Definitions Definitions
+	Sub         map[string]*Tree // key: subcommand (choke) [case insensitive]
+}
+
+// Result of parsing a level of Tree.
+type TreeLevel struct {
+	Name        string // key in parent's Sub, "" for root
+	Definitions Definitions
+	Args        []string  // non-options, arguments
+	Dashed      []string  // arguments after the divider ("--"), subset of Args
+	Bindings    []Binding // options in order of args
+}
+
+// Where an option was parsed to.
+type Binding struct {
+	Token Token // Index is in args given to Tree.Parse()
+	Level int   // index in levels, may be a parent (global option)
+}
+
+// Parses args level by level, switching to a subcommand when it's found (see chokes in Definitions.Parse()).
+//
+// Options are looked up from the current level, then parents (options of a parent are global within the subcommands).
+// Returned levels are from root to the last subcommand found.
+func (t *Tree) Parse(args []string) (levels []TreeLevel, _ error) {
+	var parsers []*Parser
+	var name string
+	offset := 0
+
+	for tree := t; tree != nil; {
+		subs := make(map[string]string, len(tree.Sub)) // lowercase: key
+		p := &Parser{Definitions: tree.Definitions}
+		for sub := range tree.Sub {
+			subs[foldCase(sub)] = sub
+			p.Chokes = append(p.Chokes, sub)
+		}
+		if err := p.Compile(); err != nil {
+			return nil, err
+		}
+
+		parsers = append(parsers, p)
+		levels = append(levels, TreeLevel{Name: name, Definitions: tree.Definitions})
+		level := &levels[len(levels)-1]
+
+		lx := p.lexer
+		lx.lookup = func(key string) *Definition {
+			_, def := lookupLevels(parsers, key)
+			return def
+		}
+
+		var next *Tree
+		var dashed bool
+		err := lx.lex(args[offset:], func(tok Token, def *Definition) error {
+			tok.Index += offset
+
+			switch tok.Kind {
+			case TokenArgument:
+				level.Args = append(level.Args, tok.Value)
+				if dashed {
+					level.Dashed = append(level.Dashed, tok.Value)
+				}
+			case TokenDivider:
+				dashed = true
+			case TokenChoke:
+				name = subs[foldCase(tok.Value)]
+				next = tree.Sub[name]
+				if next == nil {
+					next = &Tree{} // only parent options
+				}
+				offset = tok.Index + 1
+			case TokenShort, TokenLong:
+				bound, _ := lookupLevels(parsers, tok.Key)
+				level.Bindings = append(level.Bindings, Binding{Token: tok, Level: bound})
+
+				if bound == -1 {
+					bound = len(parsers) - 1 // for error
+				}
+				return parsers[bound].parseOption(tok, def)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		tree = next
+	}
+
+	return levels, nil
+}
+
+// Closest level defining key, -1 if none.
+func lookupLevels(parsers []*Parser, key string) (level int, _ *Definition) {
+	for i := len(parsers) - 1; i >= 0; i-- {
+		if def := parsers[i].opts[key]; def != nil {
+			return i, def
+		}
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,63 @@
+package harg_test
+
+import (
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeParse(t *testing.T) {
+	t.Parallel()
+
+	root := harg.Definitions{
+		"verbose": {},
+		"v":       {},
+	}
+	serve := harg.Definitions{
+		"port":    {Type: harg.Int},
+		"verbose": {Type: harg.String}, // shadows root
+	}
+	tree := harg.Tree{
+		Definitions: root,
+		Sub: map[string]*harg.Tree{
+			"Serve": {
+				Definitions: serve,
+				Sub:         map[string]*harg.Tree{"now": nil},
+			},
+			"other": {},
+		},
+	}
+
+	levels, err := tree.Parse([]string{
+		"-v", "rootarg", "serve", "--port", "80", "-v", "--verbose=loud", "NOW", "-v", "x", "--", "other",
+	})
+	require.Nil(t, err)
+	require.Len(t, levels, 3)
+
+	require.Equal(t, "", levels[0].Name)
+	require.Equal(t, []string{"rootarg"}, levels[0].Args)
+	require.Equal(t, "Serve", levels[1].Name)
+	require.Equal(t, "now", levels[2].Name)
+	require.Equal(t, []string{"x", "other"}, levels[2].Args)
+	require.Equal(t, []string{"other"}, levels[2].Dashed)
+
+	var bound [][2]int // index, level
+	for _, level := range levels {
+		for _, b := range level.Bindings {
+			bound = append(bound, [2]int{b.Token.Index, b.Level})
+		}
+	}
+	require.Equal(t, [][2]int{{0, 0}, {3, 1}, {5, 0}, {6, 1}, {8, 0}}, bound)
+
+	c, _ := root["v"].Count()
+	require.Equal(t, 3, c)
+	p, _ := serve["port"].Int()
+	require.Equal(t, 80, p)
+	s, _ := serve["verbose"].String()
+	require.Equal(t, "loud", s)
+	require.Equal(t, true, root["verbose"].Default())
+
+	_, err = tree.Parse([]string{"other", "--port=1"})
+	require.ErrorIs(t, err, harg.ErrOptionHasNoDefinition)
+}
@@ -0,0 +1,86 @@
+package harg_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisit(t *testing.T) {
+	t.Parallel()
+
+	type visited struct {
+		Kind  harg.TokenKind
+		Index int
+		Key   string
+		Value string
+	}
+
+	var got []visited
+	var names []string
+	p := harg.Parser{
+		Definitions: harg.Definitions{"name": {Type: harg.String}, "v": {}},
+		Chokes:      []string{"choke"},
+		Visit: func(tok harg.Token, def *harg.Definition) error {
+			got = append(got, visited{tok.Kind, tok.Index, tok.Key, tok.Value})
+			if def != nil && tok.Key == "name" {
+				s, _ := def.String() // parsed up to tok
+				names = append(names, s)
+			}
+			return nil
+		},
+	}
+
+	args, chokeReturn, err := p.Parse([]string{"a.txt", "--name", "x", "b.txt", "-v", "--name=y", "--", "-v", "choke"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"a.txt", "b.txt", "-v", "choke"}, args)
+	require.Nil(t, chokeReturn)
+	require.Equal(t, []visited{
+		{harg.TokenArgument, 0, "", "a.txt"},
+		{harg.TokenLong, 1, "name", "x"},
+		{harg.TokenArgument, 3, "", "b.txt"},
+		{harg.TokenShort, 4, "v", ""},
+		{harg.TokenLong, 5, "name", "y"},
+		{harg.TokenDivider, 6, "", ""},
+		{harg.TokenArgument, 7, "", "-v"},
+		{harg.TokenArgument, 8, "", "choke"},
+	}, got)
+	require.Equal(t, []string{"x", "y"}, names)
+
+	// errors stop parsing
+	errStop := errors.New("stop")
+	p = harg.Parser{Visit: func(tok harg.Token, _ *harg.Definition) error {
+		if tok.Index == 1 {
+			return errStop
+		}
+		return nil
+	}}
+	_, _, err = p.Parse([]string{"a", "b", "c"})
+	require.ErrorIs(t, err, errStop)
+}
+
+func TestVisitOnly(t *testing.T) {
+	t.Parallel()
+
+	defs := harg.Definitions{"name": {Type: harg.String}}
+	var keys []string
+	p := harg.Parser{
+		Definitions: defs,
+		Chokes:      []string{"choke"},
+		VisitOnly:   true,
+		Visit: func(tok harg.Token, def *harg.Definition) error {
+			if tok.Kind == harg.TokenShort || tok.Kind == harg.TokenLong {
+				keys = append(keys, tok.Key+"="+tok.Value)
+			}
+			return nil
+		},
+	}
+
+	_, chokeReturn, err := p.Parse([]string{"--name", "x", "--undefined", "choke", "--name=y"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"name=x", "undefined="}, keys)
+	require.Equal(t, []string{"choke", "--name=y"}, chokeReturn)
+	require.Equal(t, true, defs["name"].Default())
+}
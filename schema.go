@@ -0,0 +1,38 @@
+package hcli
+
+import (
+	"encoding/json"
+
+	"github.com/jtagcat/hcli/harg"
+)
+
+// JSON Schema of the command tree, see harg.Definitions.Schema().
+// Flags are properties by their Options and Env, SubCommands are nested (object) properties by name.
+func (c *Command) Schema() *harg.Schema {
+	defs := make(harg.Definitions)
+	for _, flag := range c.Flags {
+		flag := flag.flag()
+		def := flag.def()
+
+		for _, name := range append(flag.Options, flag.Env) {
+			if name != "" {
+				defs[name] = &def
+			}
+		}
+	}
+
+	s := defs.Schema()
+	for name, sub := range c.SubCommands {
+		subSchema := sub.Schema()
+		subSchema.Schema = "" // only on root
+
+		s.Properties[name] = subSchema
+	}
+
+	return s
+}
+
+// Schema() as indented JSON.
+func (c *Command) SchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(c.Schema(), "", "  ")
+}
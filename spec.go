@@ -0,0 +1,264 @@
+package hcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jtagcat/hcli/harg"
+	"gopkg.in/yaml.v3"
+)
+
+// Declarative Command tree, see Spec.Command().
+//
+//	action: serve
+//	flags:
+//	  - options: [port, p]
+//	    env: PORT
+//	    type: int
+//	    default: 80
+//	    usage: port to listen on
+//	commands:
+//	  version: {action: version}
+type (
+	Spec struct {
+		Action   string           `json:"action,omitempty" yaml:"action,omitempty"` // key in actions, "" for none
+		Flags    []FlagSpec       `json:"flags,omitempty" yaml:"flags,omitempty"`
+		Commands map[string]*Spec `json:"commands,omitempty" yaml:"commands,omitempty"` // SubCommands
+	}
+
+	// See Flag implementations.
+	FlagSpec struct {
+		Parent string `json:"parent,omitempty" yaml:"parent,omitempty"` // ChildFlag, other fields must be empty
+
+		Level   string   `json:"level,omitempty" yaml:"level,omitempty"` // local (default), global, parent
+		Type    string   `json:"type,omitempty" yaml:"type,omitempty"`   // harg.Type name (bool (default), string, int, duration, ...)
+		Options []string `json:"options,omitempty" yaml:"options,omitempty"`
+
+		AlsoBool bool   `json:"alsoBool,omitempty" yaml:"alsoBool,omitempty"`
+		Env      string `json:"env,omitempty" yaml:"env,omitempty"`
+		EnvCSV   bool   `json:"envCSV,omitempty" yaml:"envCSV,omitempty"`
+		File     bool   `json:"file,omitempty" yaml:"file,omitempty"`
+
+		Default   any    `json:"default,omitempty" yaml:"default,omitempty"` // value or list, converted as harg configuration
+		Sensitive bool   `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
+		Usage     string `json:"usage,omitempty" yaml:"usage,omitempty"`
+	}
+)
+
+// Decodes data to v (a *Spec), erroring on unknown fields.
+type SpecFormat func(data []byte, v any) error
+
+var (
+	SpecJSON SpecFormat = func(data []byte, v any) error {
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.DisallowUnknownFields()
+		d.UseNumber() // as harg configuration, without float64 rounding
+		return d.Decode(v)
+	}
+	SpecYAML SpecFormat = func(data []byte, v any) error {
+		d := yaml.NewDecoder(bytes.NewReader(data))
+		d.KnownFields(true)
+		return d.Decode(v)
+	}
+
+	// key: file extension, see LoadSpecFile()
+	SpecFormats = map[string]SpecFormat{
+		".json": SpecJSON,
+		".yaml": SpecYAML,
+		".yml":  SpecYAML,
+	}
+)
+
+var flagLevels = map[string]FlagLevel{
+	"":       Local,
+	"local":  Local,
+	"global": Global,
+	"parent": Parent,
+}
+
+// Command tree of Spec read from file name, format by its extension (see SpecFormats).
+func LoadSpecFile(name string, actions map[string]Func) (*Command, error) {
+	format, ok := SpecFormats[strings.ToLower(filepath.Ext(name))]
+	if !ok {
+		return nil, fmt.Errorf("spec file %s: unsupported extension", name)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec file: %w", err)
+	}
+
+	return LoadSpec(data, format, actions)
+}
+
+// Command tree of Spec in data.
+func LoadSpec(data []byte, format SpecFormat, actions map[string]Func) (*Command, error) {
+	var spec Spec
+	if err := format(data, &spec); err != nil {
+		return nil, fmt.Errorf("decoding spec: %w", err)
+	}
+
+	return spec.Command(actions)
+}
+
+// Builds the Command tree, binding Actions by name. Errors are of harg.ErrInvalidDefinition.
+func (s *Spec) Command(actions map[string]Func) (*Command, error) {
+	return s.command("", actions)
+}
+
+// path: of subcommand names, for errors
+func (s *Spec) command(path string, actions map[string]Func) (*Command, error) {
+	c := &Command{}
+
+	if s.Action != "" {
+		action, ok := actions[s.Action]
+		if !ok {
+			return nil, fmt.Errorf("command %q: action %q not found: %w", path, s.Action, harg.ErrInvalidDefinition)
+		}
+
+		c.Action = action
+	}
+
+	for i, fs := range s.Flags {
+		f, err := fs.flag()
+		if err != nil {
+			return nil, fmt.Errorf("command %q: flag %d: %w", path, i, err)
+		}
+
+		c.Flags = append(c.Flags, f)
+	}
+
+	if len(s.Commands) != 0 {
+		c.SubCommands = make(map[string]*Command, len(s.Commands))
+	}
+	for name, sub := range s.Commands {
+		if sub == nil {
+			sub = &Spec{}
+		}
+
+		subCommand, err := sub.command(strings.TrimSpace(path+" "+name), actions)
+		if err != nil {
+			return nil, err
+		}
+
+		c.SubCommands[name] = subCommand
+	}
+
+	return c, nil
+}
+
+func (fs *FlagSpec) flag() (Flag, error) {
+	if fs.Parent != "" {
+		if fs.Type != "" || fs.Level != "" || len(fs.Options) != 0 || fs.Env != "" || fs.Default != nil {
+			return nil, fmt.Errorf("parent: other fields must be empty: %w", harg.ErrInvalidDefinition)
+		}
+
+		return &ChildFlag{Parent: fs.Parent}, nil
+	}
+
+	level, ok := flagLevels[strings.ToLower(fs.Level)]
+	if !ok {
+		return nil, fmt.Errorf("level %q: %w", fs.Level, harg.ErrInvalidDefinition)
+	}
+
+	t, ok := parseType(fs.Type)
+	if !ok {
+		return nil, fmt.Errorf("type %q: %w", fs.Type, harg.ErrInvalidDefinition)
+	}
+
+	if len(fs.Options) == 0 && fs.Env == "" {
+		return nil, fmt.Errorf("options or env must be set: %w", harg.ErrInvalidDefinition)
+	}
+
+	f := &specFlag{flag{
+		Level: level, Type: t, AlsoBool: fs.AlsoBool,
+		Options: fs.Options, Env: fs.Env, EnvCSV: fs.EnvCSV, File: fs.File,
+		Sensitive: fs.Sensitive, Usage: fs.Usage,
+	}}
+
+	if fs.Default != nil {
+		var err error
+		if f.f.Default, err = specDefault(t, fs.AlsoBool, fs.Default); err != nil {
+			return nil, fmt.Errorf("default: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// harg.Type by name, "": Bool
+func parseType(name string) (harg.Type, bool) {
+	if name == "" {
+		return harg.Bool, true
+	}
+
+	for t := harg.Type(0); t <= harg.TypeMax; t++ {
+		if strings.EqualFold(t.String(), name) {
+			return t, true
+		}
+	}
+
+	return 0, false
+}
+
+// Converts a decoded default to T, or []T if a list, as a harg configuration value.
+func specDefault(t harg.Type, alsoBool bool, v any) (any, error) {
+	if _, ok := v.(map[string]any); ok {
+		return nil, fmt.Errorf("must be a value or a list: %w", harg.ErrInvalidDefinition)
+	}
+
+	const key = "default"
+	defs := harg.Definitions{key: {Type: t, AlsoBool: alsoBool}}
+
+	err := defs.ParseConfig(nil, func(io.Reader) (map[string]any, error) {
+		return map[string]any{key: specValue(v)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sl, ok := defs[key].SlAny()
+	if _, list := v.([]any); list && ok {
+		return sl, nil
+	}
+
+	if !ok || reflect.ValueOf(sl).Len() != 1 {
+		return nil, fmt.Errorf("must be a value or a list: %w", harg.ErrInvalidDefinition)
+	}
+	return reflect.ValueOf(sl).Index(0).Interface(), nil
+}
+
+// YAML ints as harg configuration (int64)
+func specValue(v any) any {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case []any:
+		values := make([]any, len(v))
+		for i, v := range v {
+			values[i] = specValue(v)
+		}
+		return values
+	default:
+		return v
+	}
+}
+
+// Flag implementation for FlagSpec.
+type specFlag struct {
+	f flag
+}
+
+func (f *specFlag) flag() flag {
+	return f.f
+}
+
+func (_ *specFlag) checkCondition(_ *harg.Definition) error {
+	return nil
+}
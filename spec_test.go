@@ -0,0 +1,104 @@
+package hcli_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jtagcat/hcli"
+	"github.com/jtagcat/hcli/harg"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+)
+
+func TestSpec(t *testing.T) {
+	t.Parallel()
+
+	actions := map[string]hcli.Func{
+		"serve": func(context.Context, []string, harg.Definitions, *slog.Logger) int { return 0 },
+	}
+
+	specs := map[string]hcli.SpecFormat{
+		"json": hcli.SpecJSON,
+		"yaml": hcli.SpecYAML,
+	}
+	docs := map[string]string{
+		"json": `{
+			"flags": [{"options": ["verbose", "v"], "level": "global"}],
+			"commands": {"serve": {
+				"action": "serve",
+				"flags": [
+					{"parent": "verbose"},
+					{"options": ["port", "p"], "env": "PORT", "type": "int", "default": 80, "usage": "port to listen on"},
+					{"options": ["timeout"], "type": "duration", "default": "5s"},
+					{"options": ["tag"], "type": "string", "default": ["a", "b"]},
+					{"options": ["limit"], "type": "int", "default": 1000000},
+					{"options": ["size"], "type": "int64", "default": 9007199254740993}
+				]
+			}}
+		}`,
+		"yaml": `
+flags:
+  - {options: [verbose, v], level: global}
+commands:
+  serve:
+    action: serve
+    flags:
+      - parent: verbose
+      - {options: [port, p], env: PORT, type: int, default: 80, usage: port to listen on}
+      - {options: [timeout], type: duration, default: 5s}
+      - {options: [tag], type: string, default: [a, b]}
+      - {options: [limit], type: int, default: 1000000}
+      - {options: [size], type: int64, default: 9007199254740993}
+`,
+	}
+
+	for name, format := range specs {
+		c, err := hcli.LoadSpec([]byte(docs[name]), format, actions)
+		require.Nil(t, err, name)
+
+		require.Len(t, c.Flags, 1, name)
+		require.Nil(t, c.Action, name)
+
+		serve := c.SubCommands["serve"]
+		require.NotNil(t, serve.Action, name)
+		require.Len(t, serve.Flags, 6, name)
+
+		schema := serve.Schema()
+		require.Equal(t, "port to listen on", schema.Properties["port"].Description, name)
+		require.Equal(t, 80, schema.Properties["port"].Default, name)
+		require.Equal(t, "5s", schema.Properties["timeout"].Default, name)
+		require.Equal(t, []any{"a", "b"}, schema.Properties["tag"].Default, name)
+		require.Equal(t, 1000000, schema.Properties["limit"].Default, name)
+		require.Equal(t, int64(9007199254740993), schema.Properties["size"].Default, name)
+		require.Equal(t, "integer", schema.Properties["port"].Type, name)
+	}
+}
+
+func TestSpecError(t *testing.T) {
+	t.Parallel()
+
+	for _, doc := range []string{
+		`{"action": "missing"}`,
+		`{"flags": [{"options": ["x"], "type": "nope"}]}`,
+		`{"flags": [{"options": ["x"], "level": "nope"}]}`,
+		`{"flags": [{"type": "string"}]}`,
+		`{"flags": [{"parent": "x", "options": ["y"]}]}`,
+		`{"flags": [{"options": ["x"], "type": "int", "default": "ten"}]}`,
+		`{"flags": [{"options": ["x"], "type": "string", "default": {}}]}`,
+		`{"flags": [{"options": ["x"], "type": "string", "default": {"a": "b"}}]}`,
+		`{"flags": [{"options": ["x"], "type": "string", "default": [{}]}]}`,
+	} {
+		_, err := hcli.LoadSpec([]byte(doc), hcli.SpecJSON, nil)
+		require.Error(t, err, doc)
+	}
+
+	_, err := hcli.LoadSpec([]byte(`{"flags": [{"options": ["x"], "type": "string", "default": {}}]}`), hcli.SpecJSON, nil)
+	require.ErrorIs(t, err, harg.ErrInvalidDefinition)
+	_, err = hcli.LoadSpec([]byte("flags: [{options: [x], default: {}}]\n"), hcli.SpecYAML, nil)
+	require.ErrorIs(t, err, harg.ErrInvalidDefinition)
+
+	_, err = hcli.LoadSpec([]byte(`{"unknown": 1}`), hcli.SpecJSON, nil)
+	require.ErrorContains(t, err, "unknown")
+	_, err = hcli.LoadSpec([]byte("unknown: 1\n"), hcli.SpecYAML, nil)
+	require.ErrorContains(t, err, "unknown")
+}